@@ -0,0 +1,99 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/kubernetes"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	k8sclient "k8s.io/client-go/kubernetes"
+)
+
+var kubeInventoryOpts struct {
+	kubeConfigPath string
+	output         string
+	format         string
+	kubeCfg        api.NetworkTransformKubernetes
+}
+
+// kubeInventoryCmd dumps a snapshot of a live cluster, to be used as the add_kubernetes inventory file
+var kubeInventoryCmd = &cobra.Command{
+	Use:   "kube-inventory",
+	Short: "Dump the Kubernetes Pods, Nodes, Services and Namespaces into an inventory file for the add_kubernetes rule",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return dumpKubeInventory()
+	},
+}
+
+func initKubeInventoryFlags() {
+	flags := kubeInventoryCmd.Flags()
+	flags.StringVar(&kubeInventoryOpts.kubeConfigPath, "kubeconfig", "", "path to kubeconfig file (default: KUBECONFIG env, $HOME/.kube/config or in-cluster config)")
+	flags.StringVarP(&kubeInventoryOpts.output, "output", "o", "", "inventory file to write (default: stdout)")
+	flags.StringVar(&kubeInventoryOpts.format, "format", "yaml", "inventory format: yaml or json")
+	flags.IntVar(&kubeInventoryOpts.kubeCfg.OwnerDepth, "owner-depth", 0, "maximum number of owner references followed to find the top-level owner (default: only ReplicaSet owners are followed)")
+	flags.StringSliceVar(&kubeInventoryOpts.kubeCfg.NamespaceLabels, "namespace-labels", nil, "namespace labels to include (namespaces aren't dumped when empty)")
+	flags.StringSliceVar(&kubeInventoryOpts.kubeCfg.PodAnnotations, "pod-annotations", nil, "Pod annotations to include")
+	rootCmd.AddCommand(kubeInventoryCmd)
+}
+
+func dumpKubeInventory() error {
+	if kubeInventoryOpts.format != "yaml" && kubeInventoryOpts.format != "json" {
+		return fmt.Errorf("invalid format %q: expected yaml or json", kubeInventoryOpts.format)
+	}
+	config, err := kubernetes.LoadConfig(kubeInventoryOpts.kubeConfigPath)
+	if err != nil {
+		return err
+	}
+	client, err := k8sclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	inventory, err := kubernetes.DumpInventory(client, &kubeInventoryOpts.kubeCfg)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if kubeInventoryOpts.output != "" {
+		file, err := os.Create(kubeInventoryOpts.output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	return writeKubeInventory(out, inventory, kubeInventoryOpts.format)
+}
+
+func writeKubeInventory(out io.Writer, inventory *kubernetes.Inventory, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(inventory)
+	}
+	encoder := yaml.NewEncoder(out)
+	defer encoder.Close()
+	return encoder.Encode(inventory)
+}
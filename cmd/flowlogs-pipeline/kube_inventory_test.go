@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/kubernetes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteKubeInventory(t *testing.T) {
+	inventory := &kubernetes.Inventory{
+		Pods: []kubernetes.InventoryObject{{
+			Name:      "pod",
+			Namespace: "ns",
+			IPs:       []string{"10.128.0.5"},
+			Owner:     &kubernetes.Owner{Type: "Deployment", Name: "web"},
+		}},
+		Nodes: []kubernetes.InventoryObject{{Name: "node", IPs: []string{"10.0.0.1"}}},
+	}
+	for _, format := range []string{"yaml", "json"} {
+		t.Run(format, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "inventory."+format)
+			file, err := os.Create(path)
+			require.NoError(t, err)
+			require.NoError(t, writeKubeInventory(file, inventory, format))
+			require.NoError(t, file.Close())
+
+			loaded, err := kubernetes.LoadInventory(path)
+			require.NoError(t, err)
+			require.Equal(t, inventory, loaded)
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"os/exec"
 	"testing"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/netobserv/flowlogs-pipeline/pkg/config"
@@ -47,7 +48,7 @@ func TestTheMain(t *testing.T) {
 func TestPipelineConfigSetup(t *testing.T) {
 	// Kube init mock
 	kdata := new(kubernetes.KubeDataMock)
-	kdata.On("InitFromConfig", "").Return(nil)
+	kdata.On("InitFromConfig", "", mock.Anything).Return(nil)
 	kubernetes.Data = kdata
 
 	js := `{
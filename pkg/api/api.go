@@ -34,6 +34,9 @@ const (
 	GenericType                  = "generic"
 	NetworkType                  = "network"
 	FilterType                   = "filter"
+	AnonymizeType                = "anonymize"
+	LookupType                   = "lookup"
+	ExplodeType                  = "explode"
 	ConnTrackType                = "conntrack"
 	NoneType                     = "none"
 	AddRegExIfRuleType           = "add_regex_if"
@@ -61,6 +64,9 @@ type API struct {
 	TransformGeneric   TransformGeneric    `yaml:"generic" doc:"## Transform Generic API\nFollowing is the supported API format for generic transformations:\n"`
 	TransformFilter    TransformFilter     `yaml:"filter" doc:"## Transform Filter API\nFollowing is the supported API format for filter transformations:\n"`
 	TransformNetwork   TransformNetwork    `yaml:"network" doc:"## Transform Network API\nFollowing is the supported API format for network transformations:\n"`
+	TransformAnonymize TransformAnonymize  `yaml:"anonymize" doc:"## Transform Anonymize API\nFollowing is the supported API format for anonymization transformations:\n"`
+	TransformLookup    TransformLookup     `yaml:"lookup" doc:"## Transform Lookup API\nFollowing is the supported API format for lookup table transformations:\n"`
+	TransformExplode   TransformExplode    `yaml:"explode" doc:"## Transform Explode API\nFollowing is the supported API format for explode transformations:\n"`
 	WriteLoki          WriteLoki           `yaml:"loki" doc:"## Write Loki API\nFollowing is the supported API format for writing to loki:\n"`
 	WriteStdout        WriteStdout         `yaml:"stdout" doc:"## Write Standard Output\nFollowing is the supported API format for writing to standard output:\n"`
 	ExtractAggregate   AggregateDefinition `yaml:"aggregates" doc:"## Aggregate metrics API\nFollowing is the supported API format for specifying metrics aggregations:\n"`
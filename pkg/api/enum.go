@@ -23,15 +23,17 @@ import (
 )
 
 type enums struct {
-	PromEncodeOperationEnum       PromEncodeOperationEnum
-	TransformNetworkOperationEnum TransformNetworkOperationEnum
-	TransformFilterOperationEnum  TransformFilterOperationEnum
-	TransformGenericOperationEnum TransformGenericOperationEnum
-	KafkaEncodeBalancerEnum       KafkaEncodeBalancerEnum
-	ConnTrackOperationEnum        ConnTrackOperationEnum
-	ConnTrackOutputRecordTypeEnum ConnTrackOutputRecordTypeEnum
-	DecoderEnum                   DecoderEnum
-	FilterOperationEnum           FilterOperationEnum
+	PromEncodeOperationEnum         PromEncodeOperationEnum
+	TransformNetworkOperationEnum   TransformNetworkOperationEnum
+	TransformFilterOperationEnum    TransformFilterOperationEnum
+	TransformGenericOperationEnum   TransformGenericOperationEnum
+	TransformAnonymizeOperationEnum TransformAnonymizeOperationEnum
+	TransformLookupMatchEnum        TransformLookupMatchEnum
+	KafkaEncodeBalancerEnum         KafkaEncodeBalancerEnum
+	ConnTrackOperationEnum          ConnTrackOperationEnum
+	ConnTrackOutputRecordTypeEnum   ConnTrackOutputRecordTypeEnum
+	DecoderEnum                     DecoderEnum
+	FilterOperationEnum             FilterOperationEnum
 }
 
 type enumNameCacheKey struct {
@@ -0,0 +1,47 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package api
+
+type TransformAnonymize struct {
+	KeyFile string                   `yaml:"keyFile,omitempty" json:"keyFile,omitempty" doc:"path to the file containing the 32-byte secret key, raw or hex-encoded, shared by all the replicas (required)"`
+	Rules   []TransformAnonymizeRule `yaml:"rules,omitempty" json:"rules,omitempty" doc:"list of anonymization rules, each includes:"`
+}
+
+const (
+	OpAnonymizeIP = "anonymize_ip"
+	OpHashMAC     = "hash_mac"
+	OpTruncateMAC = "truncate_mac"
+	OpHMAC        = "hmac"
+)
+
+type TransformAnonymizeOperationEnum struct {
+	AnonymizeIP string `yaml:"anonymize_ip" json:"anonymize_ip" doc:"prefix-preserving pseudonymization (Crypto-PAn) of an IPv4 or IPv6 address"`
+	HashMAC     string `yaml:"hash_mac" json:"hash_mac" doc:"replaces a MAC address by a keyed hash, as a locally administered address"`
+	TruncateMAC string `yaml:"truncate_mac" json:"truncate_mac" doc:"keeps only the vendor part (OUI) of a MAC address, zeroing the last 3 bytes"`
+	HMAC        string `yaml:"hmac" json:"hmac" doc:"replaces a string by its keyed hash (HMAC-SHA256 truncated to 128 bits, hex-encoded)"`
+}
+
+func TransformAnonymizeOperationName(operation string) string {
+	return GetEnumName(TransformAnonymizeOperationEnum{}, operation)
+}
+
+type TransformAnonymizeRule struct {
+	Input  string `yaml:"input,omitempty" json:"input,omitempty" doc:"entry input field"`
+	Output string `yaml:"output,omitempty" json:"output,omitempty" doc:"entry output field (default: input, replacing the original value)"`
+	Type   string `yaml:"type,omitempty" json:"type,omitempty" enum:"TransformAnonymizeOperationEnum" doc:"one of the following:"`
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package api
+
+type TransformExplode struct {
+	Splits     []TransformExplodeSplit `yaml:"splits,omitempty" json:"splits,omitempty" doc:"splits of the records, e.g. one per direction; one record is emitted per split, each includes:"`
+	Fields     []TransformExplodeField `yaml:"fields,omitempty" json:"fields,omitempty" doc:"fields renamed in the records emitted for each split, each includes:"`
+	Lists      []TransformExplodeList  `yaml:"lists,omitempty" json:"lists,omitempty" doc:"list fields exploded in parallel, instead of splits: one record is emitted per element, each includes:"`
+	IndexField string                  `yaml:"indexField,omitempty" json:"indexField,omitempty" doc:"field set to the index of the split or of the list element (optional)"`
+}
+
+type TransformExplodeSplit struct {
+	Vars map[string]string      `yaml:"vars,omitempty" json:"vars,omitempty" doc:"values of the variables of the fields templates, e.g. dir: AB"`
+	Set  map[string]interface{} `yaml:"set,omitempty" json:"set,omitempty" doc:"constant fields set in the emitted record, e.g. Direction: AB (optional)"`
+}
+
+type TransformExplodeField struct {
+	Input  string `yaml:"input,omitempty" json:"input,omitempty" doc:"template of the input field, in which {var} is replaced by the value of the split variable var, e.g. Bytes_{dir}"`
+	Output string `yaml:"output,omitempty" json:"output,omitempty" doc:"template of the output field, e.g. Bytes"`
+}
+
+type TransformExplodeList struct {
+	Field  string `yaml:"field,omitempty" json:"field,omitempty" doc:"list field"`
+	Output string `yaml:"output,omitempty" json:"output,omitempty" doc:"field set to the element (default: field)"`
+}
+
+func (l *TransformExplodeList) GetOutput() string {
+	if l.Output == "" {
+		return l.Field
+	}
+	return l.Output
+}
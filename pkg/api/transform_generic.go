@@ -35,6 +35,7 @@ type GenericTransformRule struct {
 	Input      string `yaml:"input,omitempty" json:"input,omitempty" doc:"entry input field"`
 	Output     string `yaml:"output,omitempty" json:"output,omitempty" doc:"entry output field"`
 	Multiplier int    `yaml:"multiplier,omitempty" json:"multiplier,omitempty" doc:"scaling factor to compenstate for sampling"`
+	If         string `yaml:"if,omitempty" json:"if,omitempty" doc:"optional condition on the entry fields for the rule to run, e.g. has(SamplingRate); functions has(field) and inCIDR(ip, cidr...) are available"`
 }
 
 type GenericTransform []GenericTransformRule
@@ -0,0 +1,81 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package api
+
+import "time"
+
+type TransformLookup struct {
+	File         string                  `yaml:"file,omitempty" json:"file,omitempty" doc:"path to the table file, either CSV with a header line (.csv extension) or a JSON array of objects (any other extension), reloaded when modified (required)"`
+	ReloadPeriod *Duration               `yaml:"reloadPeriod,omitempty" json:"reloadPeriod,omitempty" doc:"period for checking the table file for changes (optional, default: 30s)"`
+	Keys         []TransformLookupKey    `yaml:"keys,omitempty" json:"keys,omitempty" doc:"record fields forming the key of the table rows, each includes:"`
+	Columns      []TransformLookupColumn `yaml:"columns,omitempty" json:"columns,omitempty" doc:"table columns copied into the records, each includes:"`
+}
+
+func (tl *TransformLookup) GetReloadPeriod() time.Duration {
+	if tl.ReloadPeriod == nil || tl.ReloadPeriod.Duration == 0 {
+		return 30 * time.Second
+	}
+	return tl.ReloadPeriod.Duration
+}
+
+const (
+	LookupMatchExact = "exact"
+	LookupMatchCIDR  = "cidr"
+)
+
+type TransformLookupMatchEnum struct {
+	Exact string `yaml:"exact" json:"exact" doc:"the field value is equal to the column value"`
+	CIDR  string `yaml:"cidr" json:"cidr" doc:"the field IP is contained in the column CIDR, the most specific CIDR being used; only one key can use it"`
+}
+
+func TransformLookupMatchName(match string) string {
+	return GetEnumName(TransformLookupMatchEnum{}, match)
+}
+
+type TransformLookupKey struct {
+	Field  string `yaml:"field,omitempty" json:"field,omitempty" doc:"record field"`
+	Column string `yaml:"column,omitempty" json:"column,omitempty" doc:"table column matched against the field (default: field)"`
+	Match  string `yaml:"match,omitempty" json:"match,omitempty" enum:"TransformLookupMatchEnum" doc:"one of the following (default: exact):"`
+}
+
+func (k *TransformLookupKey) GetColumn() string {
+	if k.Column == "" {
+		return k.Field
+	}
+	return k.Column
+}
+
+func (k *TransformLookupKey) GetMatch() string {
+	if k.Match == "" {
+		return LookupMatchExact
+	}
+	return k.Match
+}
+
+type TransformLookupColumn struct {
+	Column  string      `yaml:"column,omitempty" json:"column,omitempty" doc:"table column"`
+	Output  string      `yaml:"output,omitempty" json:"output,omitempty" doc:"record output field (default: column)"`
+	Default interface{} `yaml:"default,omitempty" json:"default,omitempty" doc:"value set when no row matches or the row has no value for the column (optional, default: no field set)"`
+}
+
+func (c *TransformLookupColumn) GetOutput() string {
+	if c.Output == "" {
+		return c.Column
+	}
+	return c.Output
+}
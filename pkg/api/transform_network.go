@@ -17,13 +17,44 @@
 
 package api
 
+import (
+	"path/filepath"
+	"strings"
+	"time"
+)
+
 type TransformNetwork struct {
-	Rules          NetworkTransformRules         `yaml:"rules" json:"rules" doc:"list of transform rules, each includes:"`
-	KubeConfigPath string                        `yaml:"kubeConfigPath,omitempty" json:"kubeConfigPath,omitempty" doc:"path to kubeconfig file (optional)"`
-	ServicesFile   string                        `yaml:"servicesFile,omitempty" json:"servicesFile,omitempty" doc:"path to services file (optional, default: /etc/services)"`
-	ProtocolsFile  string                        `yaml:"protocolsFile,omitempty" json:"protocolsFile,omitempty" doc:"path to protocols file (optional, default: /etc/protocols)"`
-	IPCategories   []NetworkTransformIPCategory  `yaml:"ipCategories,omitempty" json:"ipCategories,omitempty" doc:"configure IP categories"`
-	DirectionInfo  NetworkTransformDirectionInfo `yaml:"directionInfo,omitempty" json:"directionInfo,omitempty" doc:"information to reinterpret flow direction (optional, to use with reinterpret_direction rule)"`
+	Rules                  NetworkTransformRules               `yaml:"rules" json:"rules" doc:"list of transform rules, each includes:"`
+	KubeConfigPath         string                              `yaml:"kubeConfigPath,omitempty" json:"kubeConfigPath,omitempty" doc:"path to kubeconfig file (optional)"`
+	Kubernetes             *NetworkTransformKubernetes         `yaml:"kubernetes,omitempty" json:"kubernetes,omitempty" doc:"kubernetes enrichment settings (optional, to use with add_kubernetes rule)"`
+	LocationDBPath         string                              `yaml:"locationDBPath,omitempty" json:"locationDBPath,omitempty" doc:"path to a MaxMind GeoLite2-City (.mmdb) or IP2Location (.BIN) database, reloaded when modified (optional, to use with add_location rule; default: download the IP2Location LITE database at startup)"`
+	LocationDBChecksumPath string                              `yaml:"locationDBChecksumPath,omitempty" json:"locationDBChecksumPath,omitempty" doc:"path to a file containing the SHA-256 checksum of the location database, in sha256sum format; the database is rejected when it doesn't match (optional)"`
+	ServicesFile           string                              `yaml:"servicesFile,omitempty" json:"servicesFile,omitempty" doc:"path to services file (optional, default: /etc/services)"`
+	ProtocolsFile          string                              `yaml:"protocolsFile,omitempty" json:"protocolsFile,omitempty" doc:"path to protocols file (optional, default: /etc/protocols)"`
+	ServiceOverrides       []NetworkTransformServiceOverride   `yaml:"serviceOverrides,omitempty" json:"serviceOverrides,omitempty" doc:"service names taking precedence over the services file (optional, to use with add_service and add_server_port rules)"`
+	ServiceOverridesFile   string                              `yaml:"serviceOverridesFile,omitempty" json:"serviceOverridesFile,omitempty" doc:"path to a file in the services file format, whose entries take precedence over the services file (optional)"`
+	ServerPortInfo         *NetworkTransformServerPortInfo     `yaml:"serverPortInfo,omitempty" json:"serverPortInfo,omitempty" doc:"fields used to infer the server port (optional, to use with add_server_port rule)"`
+	ICMPInfo               *NetworkTransformICMPInfo           `yaml:"icmpInfo,omitempty" json:"icmpInfo,omitempty" doc:"fields providing ICMP information (optional, to use with decode_icmp rule)"`
+	CommunityIDInfo        *NetworkTransformCommunityIDInfo    `yaml:"communityIDInfo,omitempty" json:"communityIDInfo,omitempty" doc:"fields and seed used to compute the Community ID (optional, to use with add_community_id rule)"`
+	SamplingInfo           *NetworkTransformSamplingInfo       `yaml:"samplingInfo,omitempty" json:"samplingInfo,omitempty" doc:"sampling rate fields, fallback rates and counter fields (optional, to use with scale_sampling rule)"`
+	NetworkPolicyInfo      *NetworkTransformNetworkPolicyInfo  `yaml:"networkPolicyInfo,omitempty" json:"networkPolicyInfo,omitempty" doc:"fields evaluated against the NetworkPolicies (optional, to use with add_network_policy rule)"`
+	IPCategories           []NetworkTransformIPCategory        `yaml:"ipCategories,omitempty" json:"ipCategories,omitempty" doc:"configure IP categories"`
+	IPCategoriesFiles      []string                            `yaml:"ipCategoriesFiles,omitempty" json:"ipCategoriesFiles,omitempty" doc:"list of YAML or CSV files providing additional IP categories; they are reloaded when modified (optional)"`
+	ReloadPeriod           *Duration                           `yaml:"reloadPeriod,omitempty" json:"reloadPeriod,omitempty" doc:"period for checking external files for changes (optional, default: 30s)"`
+	SubnetLabels           []NetworkTransformSubnetLabel       `yaml:"subnetLabels,omitempty" json:"subnetLabels,omitempty" doc:"configure subnet labels (optional, to use with add_subnet_label rule)"`
+	ASNDBPath              string                              `yaml:"asnDBPath,omitempty" json:"asnDBPath,omitempty" doc:"path to a MaxMind GeoLite2-ASN (.mmdb) or IP2Location ASN (.BIN) database, reloaded when modified (required by add_asn rule)"`
+	MacVendorFiles         []string                            `yaml:"macVendorFiles,omitempty" json:"macVendorFiles,omitempty" doc:"paths to IEEE OUI registry files in CSV format (MA-L, MA-M and/or MA-S), reloaded when modified (required by add_mac_vendor rule)"`
+	ThreatIntel            *NetworkTransformThreatIntel        `yaml:"threatIntel,omitempty" json:"threatIntel,omitempty" doc:"threat intelligence blocklists matched against the flows (required by add_threat_intel rule)"`
+	ReverseDNS             *NetworkTransformReverseDNS         `yaml:"reverseDNS,omitempty" json:"reverseDNS,omitempty" doc:"reverse DNS resolution settings (optional, to use with add_reverse_dns rule)"`
+	DirectionInfo          NetworkTransformDirectionInfo       `yaml:"directionInfo,omitempty" json:"directionInfo,omitempty" doc:"information to reinterpret flow direction (optional, to use with reinterpret_direction rule)"`
+	DirectionInference     *NetworkTransformDirectionInference `yaml:"directionInference,omitempty" json:"directionInference,omitempty" doc:"internal networks and fields used to infer the flow direction (required by infer_direction rule)"`
+}
+
+func (tn *TransformNetwork) GetReloadPeriod() time.Duration {
+	if tn.ReloadPeriod == nil || tn.ReloadPeriod.Duration == 0 {
+		return 30 * time.Second
+	}
+	return tn.ReloadPeriod.Duration
 }
 
 func (tn *TransformNetwork) GetServiceFiles() (string, string) {
@@ -46,18 +77,46 @@ const (
 	OpAddService           = "add_service"
 	OpAddKubernetes        = "add_kubernetes"
 	OpReinterpretDirection = "reinterpret_direction"
+	OpInferDirection       = "infer_direction"
+	OpAddThreatIntel       = "add_threat_intel"
 	OpAddIPCategory        = "add_ip_category"
+	OpAddSubnetLabel       = "add_subnet_label"
+	OpAddReverseDNS        = "add_reverse_dns"
+	OpAddASN               = "add_asn"
+	OpAddServerPort        = "add_server_port"
+	OpDecodeProtocol       = "decode_protocol"
+	OpDecodeEtherType      = "decode_ethertype"
+	OpDecodeTCPFlags       = "decode_tcp_flags"
+	OpDecodeICMP           = "decode_icmp"
+	OpAddNetworkPolicy     = "add_network_policy"
+	OpAddMacVendor         = "add_mac_vendor"
+	OpAddCommunityID       = "add_community_id"
+	OpScaleSampling        = "scale_sampling"
 )
 
 type TransformNetworkOperationEnum struct {
 	AddRegExIf           string `yaml:"add_regex_if" json:"add_regex_if" doc:"add output field if input field satisfies regex pattern from parameters field"`
 	AddIf                string `yaml:"add_if" json:"add_if" doc:"add output field if input field satisfies criteria from parameters field"`
-	AddSubnet            string `yaml:"add_subnet" json:"add_subnet" doc:"add output subnet field from input field and prefix length from parameters field"`
+	AddSubnet            string `yaml:"add_subnet" json:"add_subnet" doc:"add output subnet field from input field and prefix length from parameters field; use e.g. /24,/64 for distinct IPv4 and IPv6 prefix lengths"`
 	AddLocation          string `yaml:"add_location" json:"add_location" doc:"add output location fields from input"`
 	AddService           string `yaml:"add_service" json:"add_service" doc:"add output network service field from input port and parameters protocol field"`
 	AddKubernetes        string `yaml:"add_kubernetes" json:"add_kubernetes" doc:"add output kubernetes fields from input"`
 	ReinterpretDirection string `yaml:"reinterpret_direction" json:"reinterpret_direction" doc:"reinterpret flow direction at a higher level than the interface"`
+	InferDirection       string `yaml:"infer_direction" json:"infer_direction" doc:"add output direction field (ingress, egress, internal, transit or external) from the IP categories of both sides configured in directionInference, optionally swapping the source and destination to get a client to server orientation"`
 	AddIPCategory        string `yaml:"add_ip_category" json:"add_ip_category" doc:"categorize IPs based on known subnets configuration"`
+	AddReverseDNS        string `yaml:"add_reverse_dns" json:"add_reverse_dns" doc:"add output host name field from the reverse DNS resolution of the input IP; unresolved IPs are resolved in background and skipped"`
+	AddSubnetLabel       string `yaml:"add_subnet_label" json:"add_subnet_label" doc:"add output label and CIDR fields from the longest configured subnet label matching the input IP"`
+	AddServerPort        string `yaml:"add_server_port" json:"add_server_port" doc:"add output server port and service name fields, inferring which side of the flow is the server"`
+	DecodeProtocol       string `yaml:"decode_protocol" json:"decode_protocol" doc:"add output protocol name field from the input protocol number, using the protocols file"`
+	DecodeEtherType      string `yaml:"decode_ethertype" json:"decode_ethertype" doc:"add output EtherType name field from the input EtherType number"`
+	DecodeTCPFlags       string `yaml:"decode_tcp_flags" json:"decode_tcp_flags" doc:"add output TCP flags names field from the input TCP flags bitmask; set parameters to list to get a list instead of a comma-separated string"`
+	DecodeICMP           string `yaml:"decode_icmp" json:"decode_icmp" doc:"add output ICMP type and code names fields, from the fields configured in icmpInfo"`
+	AddNetworkPolicy     string `yaml:"add_network_policy" json:"add_network_policy" doc:"add output PolicyVerdict and PolicyName fields, evaluating the flow against the Kubernetes NetworkPolicies"`
+	AddASN               string `yaml:"add_asn" json:"add_asn" doc:"add output Autonomous System number and organization fields from the input IP, using the database from asnDBPath"`
+	AddCommunityID       string `yaml:"add_community_id" json:"add_community_id" doc:"add output Community ID v1 field (default: CommunityID), the standard flow hash used by Zeek and Suricata, from the fields configured in communityIDInfo"`
+	AddThreatIntel       string `yaml:"add_threat_intel" json:"add_threat_intel" doc:"add output ThreatMatch, ThreatSource and ThreatCategory fields when the flow IPs or domains match the blocklists configured in threatIntel"`
+	AddMacVendor         string `yaml:"add_mac_vendor" json:"add_mac_vendor" doc:"add output MAC vendor field from the input MAC address, using the longest matching assignment from macVendorFiles, and flag locally administered and multicast addresses"`
+	ScaleSampling        string `yaml:"scale_sampling" json:"scale_sampling" doc:"multiply the counter fields configured in samplingInfo by the sampling rate of the flow, or of its exporter, keeping the raw values in <field>_Raw and flagging the record in output field (default: SamplingUpscaled)"`
 }
 
 func TransformNetworkOperationName(operation string) string {
@@ -70,6 +129,7 @@ type NetworkTransformRule struct {
 	Type       string `yaml:"type,omitempty" json:"type,omitempty" enum:"TransformNetworkOperationEnum" doc:"one of the following:"`
 	Parameters string `yaml:"parameters,omitempty" json:"parameters,omitempty" doc:"parameters specific to type"`
 	Assignee   string `yaml:"assignee,omitempty" json:"assignee,omitempty" doc:"value needs to assign to output field"`
+	If         string `yaml:"if,omitempty" json:"if,omitempty" doc:"optional condition on the entry fields for the rule to run, e.g. inCIDR(SrcAddr, '10.128.0.0/14'); functions has(field) and inCIDR(ip, cidr...) are available"`
 }
 
 type NetworkTransformDirectionInfo struct {
@@ -80,9 +140,421 @@ type NetworkTransformDirectionInfo struct {
 	IfDirectionField   string `yaml:"ifDirectionField,omitempty" json:"ifDirectionField,omitempty" doc:"interface-level field for flow direction, to create in output"`
 }
 
+type NetworkTransformDirectionInference struct {
+	InternalCategories []string `yaml:"internalCategories,omitempty" json:"internalCategories,omitempty" doc:"names of the IP categories (from ipCategories and ipCategoriesFiles) of the internal networks"`
+	SrcIPField         string   `yaml:"srcIPField,omitempty" json:"srcIPField,omitempty" doc:"source IP field (default: SrcAddr)"`
+	DstIPField         string   `yaml:"dstIPField,omitempty" json:"dstIPField,omitempty" doc:"destination IP field (default: DstAddr)"`
+	SwapToClientServer bool     `yaml:"swapToClientServer,omitempty" json:"swapToClientServer,omitempty" doc:"swap the source and destination fields when the source is the server side, inferred as in add_server_port rule with the serverPortInfo fields (default: false)"`
+	SrcPrefix          string   `yaml:"srcPrefix,omitempty" json:"srcPrefix,omitempty" doc:"prefix of the source fields swapped with the destination fields having the same suffix (default: Src)"`
+	DstPrefix          string   `yaml:"dstPrefix,omitempty" json:"dstPrefix,omitempty" doc:"prefix of the destination fields swapped with the source fields having the same suffix (default: Dst)"`
+}
+
+func (i *NetworkTransformDirectionInference) GetSrcIPField() string {
+	if i == nil || i.SrcIPField == "" {
+		return "SrcAddr"
+	}
+	return i.SrcIPField
+}
+
+func (i *NetworkTransformDirectionInference) GetDstIPField() string {
+	if i == nil || i.DstIPField == "" {
+		return "DstAddr"
+	}
+	return i.DstIPField
+}
+
+func (i *NetworkTransformDirectionInference) GetSrcPrefix() string {
+	if i == nil || i.SrcPrefix == "" {
+		return "Src"
+	}
+	return i.SrcPrefix
+}
+
+func (i *NetworkTransformDirectionInference) GetDstPrefix() string {
+	if i == nil || i.DstPrefix == "" {
+		return "Dst"
+	}
+	return i.DstPrefix
+}
+
+type NetworkTransformKubernetes struct {
+	Clusters         []NetworkTransformKubeCluster         `yaml:"clusters,omitempty" json:"clusters,omitempty" doc:"several named clusters, each with its own kubeconfig or inventory file, replacing kubeConfigPath and inventoryFile (optional)"`
+	ClusterField     string                                `yaml:"clusterField,omitempty" json:"clusterField,omitempty" doc:"record field choosing the cluster where IPs are looked up, matched against the clusters values and CIDRs (required with several clusters)"`
+	InventoryFile    string                                `yaml:"inventoryFile,omitempty" json:"inventoryFile,omitempty" doc:"path to a YAML or JSON snapshot of Pods, Nodes, Services and Namespaces, used instead of a live cluster and reloaded when modified (optional)"`
+	NamespaceLabels  []string                              `yaml:"namespaceLabels,omitempty" json:"namespaceLabels,omitempty" doc:"namespace labels to add, as <output>_NamespaceLabel_<key> fields"`
+	PodAnnotations   []string                              `yaml:"podAnnotations,omitempty" json:"podAnnotations,omitempty" doc:"Pod annotations to add, as <output>_Annotation_<key> fields"`
+	NodeLabels       []string                              `yaml:"nodeLabels,omitempty" json:"nodeLabels,omitempty" doc:"labels of the Node, or of the Node hosting the Pod, to add as <output>_NodeLabel_<key> fields (e.g. topology.kubernetes.io/zone)"`
+	CNIPlugins       []string                              `yaml:"cniPlugins,omitempty" json:"cniPlugins,omitempty" doc:"CNI plugins providing additional Node IPs, such as tunnel IPs: ovn-kubernetes, calico and/or cilium (default: all)"`
+	OwnerDepth       int                                   `yaml:"ownerDepth,omitempty" json:"ownerDepth,omitempty" doc:"maximum number of owner references followed to find the top-level owner of a Pod, e.g. Pod to ReplicaSet to Deployment is 2; when set, ReplicaSets, Deployments, StatefulSets, DaemonSets and Jobs are watched (default: only the ReplicaSet owners of Pods are followed, e.g. to their Deployment)"`
+	ServiceEndpoints *NetworkTransformKubeServiceEndpoints `yaml:"serviceEndpoints,omitempty" json:"serviceEndpoints,omitempty" doc:"watch EndpointSlices to add the Service backing a Pod IP and port, and the candidate endpoints of a Service IP (optional)"`
+	TimestampField   string                                `yaml:"timestampField,omitempty" json:"timestampField,omitempty" doc:"field with the flow time, in milliseconds, used to look up IPs that were reassigned since the flow (default: TimeFlowEndMs)"`
+	IPHistorySize    int                                   `yaml:"ipHistorySize,omitempty" json:"ipHistorySize,omitempty" doc:"maximum number of past IP assignments of deleted Pods kept to enrich delayed flows; -1 disables the history (default: 10000)"`
+	IPHistoryMaxAge  *Duration                             `yaml:"ipHistoryMaxAge,omitempty" json:"ipHistoryMaxAge,omitempty" doc:"maximum time past IP assignments are kept after the Pod deletion (default: 1h)"`
+}
+
+type NetworkTransformKubeCluster struct {
+	Name           string   `yaml:"name" json:"name" doc:"name of the cluster, added as <output>_Cluster field"`
+	KubeConfigPath string   `yaml:"kubeConfigPath,omitempty" json:"kubeConfigPath,omitempty" doc:"path to the cluster kubeconfig file"`
+	InventoryFile  string   `yaml:"inventoryFile,omitempty" json:"inventoryFile,omitempty" doc:"path to the cluster inventory file, used instead of kubeConfigPath"`
+	Values         []string `yaml:"values,omitempty" json:"values,omitempty" doc:"values of the cluster field selecting this cluster (e.g. Kafka topics)"`
+	CIDRs          []string `yaml:"cidrs,omitempty" json:"cidrs,omitempty" doc:"IP ranges of the cluster field selecting this cluster (e.g. for AgentIP). A cluster without values nor CIDRs is used when no other cluster matches"`
+}
+
+type NetworkTransformKubeServiceEndpoints struct {
+	PortFields    map[string]string `yaml:"portFields,omitempty" json:"portFields,omitempty" doc:"port field of each add_kubernetes input field (default: SrcPort for SrcAddr and DstPort for DstAddr)"`
+	ProtocolField string            `yaml:"protocolField,omitempty" json:"protocolField,omitempty" doc:"protocol name or number field (default: Proto)"`
+}
+
+// GetPortField returns the port field matching the add_kubernetes input field, or an empty string if none
+func (e *NetworkTransformKubeServiceEndpoints) GetPortField(input string) string {
+	if e == nil || len(e.PortFields) == 0 {
+		return map[string]string{"SrcAddr": "SrcPort", "DstAddr": "DstPort"}[input]
+	}
+	return e.PortFields[input]
+}
+
+func (e *NetworkTransformKubeServiceEndpoints) GetProtocolField() string {
+	if e == nil || e.ProtocolField == "" {
+		return "Proto"
+	}
+	return e.ProtocolField
+}
+
+func (k *NetworkTransformKubernetes) GetInventoryFile() string {
+	if k == nil {
+		return ""
+	}
+	return k.InventoryFile
+}
+
+// GetOwnerDepth returns the configured owner depth, 0 meaning that it isn't set
+func (k *NetworkTransformKubernetes) GetOwnerDepth() int {
+	if k == nil || k.OwnerDepth <= 0 {
+		return 0
+	}
+	return k.OwnerDepth
+}
+
+func (k *NetworkTransformKubernetes) GetTimestampField() string {
+	if k == nil || k.TimestampField == "" {
+		return "TimeFlowEndMs"
+	}
+	return k.TimestampField
+}
+
+// GetIPHistorySize returns the maximum number of past IP assignments to keep, 0 meaning that the history is disabled
+func (k *NetworkTransformKubernetes) GetIPHistorySize() int {
+	if k == nil || k.IPHistorySize == 0 {
+		return 10000
+	}
+	if k.IPHistorySize < 0 {
+		return 0
+	}
+	return k.IPHistorySize
+}
+
+func (k *NetworkTransformKubernetes) GetIPHistoryMaxAge() time.Duration {
+	if k == nil || k.IPHistoryMaxAge == nil || k.IPHistoryMaxAge.Duration == 0 {
+		return time.Hour
+	}
+	return k.IPHistoryMaxAge.Duration
+}
+
+type NetworkTransformServiceOverride struct {
+	Port     int    `yaml:"port" json:"port" doc:"port number"`
+	Protocol string `yaml:"protocol,omitempty" json:"protocol,omitempty" doc:"protocol name or number; when omitted, the override applies to any protocol"`
+	Name     string `yaml:"name" json:"name" doc:"service name"`
+}
+
+type NetworkTransformServerPortInfo struct {
+	SrcPortField  string `yaml:"srcPortField,omitempty" json:"srcPortField,omitempty" doc:"source port field (default: SrcPort)"`
+	DstPortField  string `yaml:"dstPortField,omitempty" json:"dstPortField,omitempty" doc:"destination port field (default: DstPort)"`
+	ProtocolField string `yaml:"protocolField,omitempty" json:"protocolField,omitempty" doc:"protocol name or number field (default: Proto)"`
+}
+
+func (i *NetworkTransformServerPortInfo) GetSrcPortField() string {
+	if i == nil || i.SrcPortField == "" {
+		return "SrcPort"
+	}
+	return i.SrcPortField
+}
+
+func (i *NetworkTransformServerPortInfo) GetDstPortField() string {
+	if i == nil || i.DstPortField == "" {
+		return "DstPort"
+	}
+	return i.DstPortField
+}
+
+func (i *NetworkTransformServerPortInfo) GetProtocolField() string {
+	if i == nil || i.ProtocolField == "" {
+		return "Proto"
+	}
+	return i.ProtocolField
+}
+
+type NetworkTransformICMPInfo struct {
+	TypeField     string `yaml:"typeField,omitempty" json:"typeField,omitempty" doc:"ICMP type field (default: IcmpType)"`
+	CodeField     string `yaml:"codeField,omitempty" json:"codeField,omitempty" doc:"ICMP code field (default: IcmpCode)"`
+	ProtocolField string `yaml:"protocolField,omitempty" json:"protocolField,omitempty" doc:"protocol number field, to distinguish ICMP from ICMPv6 (default: Proto)"`
+}
+
+func (i *NetworkTransformICMPInfo) GetTypeField() string {
+	if i == nil || i.TypeField == "" {
+		return "IcmpType"
+	}
+	return i.TypeField
+}
+
+func (i *NetworkTransformICMPInfo) GetCodeField() string {
+	if i == nil || i.CodeField == "" {
+		return "IcmpCode"
+	}
+	return i.CodeField
+}
+
+func (i *NetworkTransformICMPInfo) GetProtocolField() string {
+	if i == nil || i.ProtocolField == "" {
+		return "Proto"
+	}
+	return i.ProtocolField
+}
+
+type NetworkTransformCommunityIDInfo struct {
+	SrcIPField    string `yaml:"srcIPField,omitempty" json:"srcIPField,omitempty" doc:"source IP field (default: SrcAddr)"`
+	DstIPField    string `yaml:"dstIPField,omitempty" json:"dstIPField,omitempty" doc:"destination IP field (default: DstAddr)"`
+	SrcPortField  string `yaml:"srcPortField,omitempty" json:"srcPortField,omitempty" doc:"source port field (default: SrcPort)"`
+	DstPortField  string `yaml:"dstPortField,omitempty" json:"dstPortField,omitempty" doc:"destination port field (default: DstPort)"`
+	ProtocolField string `yaml:"protocolField,omitempty" json:"protocolField,omitempty" doc:"protocol number field (default: Proto)"`
+	ICMPTypeField string `yaml:"icmpTypeField,omitempty" json:"icmpTypeField,omitempty" doc:"ICMP type field, used instead of the source port for ICMP and ICMPv6 (default: IcmpType)"`
+	ICMPCodeField string `yaml:"icmpCodeField,omitempty" json:"icmpCodeField,omitempty" doc:"ICMP code field, used instead of the destination port for ICMP and ICMPv6 (default: IcmpCode)"`
+	Seed          uint16 `yaml:"seed,omitempty" json:"seed,omitempty" doc:"seed of the hash, which must be the same as in the other tools computing the Community ID (default: 0)"`
+}
+
+func (i *NetworkTransformCommunityIDInfo) GetSrcIPField() string {
+	if i == nil || i.SrcIPField == "" {
+		return "SrcAddr"
+	}
+	return i.SrcIPField
+}
+
+func (i *NetworkTransformCommunityIDInfo) GetDstIPField() string {
+	if i == nil || i.DstIPField == "" {
+		return "DstAddr"
+	}
+	return i.DstIPField
+}
+
+func (i *NetworkTransformCommunityIDInfo) GetSrcPortField() string {
+	if i == nil || i.SrcPortField == "" {
+		return "SrcPort"
+	}
+	return i.SrcPortField
+}
+
+func (i *NetworkTransformCommunityIDInfo) GetDstPortField() string {
+	if i == nil || i.DstPortField == "" {
+		return "DstPort"
+	}
+	return i.DstPortField
+}
+
+func (i *NetworkTransformCommunityIDInfo) GetProtocolField() string {
+	if i == nil || i.ProtocolField == "" {
+		return "Proto"
+	}
+	return i.ProtocolField
+}
+
+func (i *NetworkTransformCommunityIDInfo) GetICMPTypeField() string {
+	if i == nil || i.ICMPTypeField == "" {
+		return "IcmpType"
+	}
+	return i.ICMPTypeField
+}
+
+func (i *NetworkTransformCommunityIDInfo) GetICMPCodeField() string {
+	if i == nil || i.ICMPCodeField == "" {
+		return "IcmpCode"
+	}
+	return i.ICMPCodeField
+}
+
+func (i *NetworkTransformCommunityIDInfo) GetSeed() uint16 {
+	if i == nil {
+		return 0
+	}
+	return i.Seed
+}
+
+type NetworkTransformSamplingInfo struct {
+	RateField     string                             `yaml:"rateField,omitempty" json:"rateField,omitempty" doc:"field providing the sampling rate of the flow, e.g. 100 when 1 out of 100 packets is sampled; 0 means unknown (default: SamplingRate)"`
+	ExporterField string                             `yaml:"exporterField,omitempty" json:"exporterField,omitempty" doc:"field providing the exporter IP, to find its fallback rate (default: SamplerAddress)"`
+	Exporters     []NetworkTransformExporterSampling `yaml:"exporters,omitempty" json:"exporters,omitempty" doc:"fallback sampling rates of the exporters, used when the flow doesn't provide its rate"`
+	DefaultRate   int                                `yaml:"defaultRate,omitempty" json:"defaultRate,omitempty" doc:"fallback sampling rate of the other exporters (default: 0, i.e. flows without known rate are left unchanged)"`
+	Fields        []string                           `yaml:"fields,omitempty" json:"fields,omitempty" doc:"counter fields to scale (default: Bytes and Packets)"`
+}
+
+type NetworkTransformExporterSampling struct {
+	CIDRs []string `yaml:"cidrs,omitempty" json:"cidrs,omitempty" doc:"IPs or CIDRs of the exporters"`
+	Rate  int      `yaml:"rate,omitempty" json:"rate,omitempty" doc:"sampling rate of the exporters"`
+}
+
+func (i *NetworkTransformSamplingInfo) GetRateField() string {
+	if i == nil || i.RateField == "" {
+		return "SamplingRate"
+	}
+	return i.RateField
+}
+
+func (i *NetworkTransformSamplingInfo) GetExporterField() string {
+	if i == nil || i.ExporterField == "" {
+		return "SamplerAddress"
+	}
+	return i.ExporterField
+}
+
+func (i *NetworkTransformSamplingInfo) GetFields() []string {
+	if i == nil || len(i.Fields) == 0 {
+		return []string{"Bytes", "Packets"}
+	}
+	return i.Fields
+}
+
+type NetworkTransformNetworkPolicyInfo struct {
+	SrcIPField    string `yaml:"srcIPField,omitempty" json:"srcIPField,omitempty" doc:"source IP field (default: SrcAddr)"`
+	DstIPField    string `yaml:"dstIPField,omitempty" json:"dstIPField,omitempty" doc:"destination IP field (default: DstAddr)"`
+	DstPortField  string `yaml:"dstPortField,omitempty" json:"dstPortField,omitempty" doc:"destination port field (default: DstPort)"`
+	ProtocolField string `yaml:"protocolField,omitempty" json:"protocolField,omitempty" doc:"protocol name or number field (default: Proto)"`
+}
+
+func (i *NetworkTransformNetworkPolicyInfo) GetSrcIPField() string {
+	if i == nil || i.SrcIPField == "" {
+		return "SrcAddr"
+	}
+	return i.SrcIPField
+}
+
+func (i *NetworkTransformNetworkPolicyInfo) GetDstIPField() string {
+	if i == nil || i.DstIPField == "" {
+		return "DstAddr"
+	}
+	return i.DstIPField
+}
+
+func (i *NetworkTransformNetworkPolicyInfo) GetDstPortField() string {
+	if i == nil || i.DstPortField == "" {
+		return "DstPort"
+	}
+	return i.DstPortField
+}
+
+func (i *NetworkTransformNetworkPolicyInfo) GetProtocolField() string {
+	if i == nil || i.ProtocolField == "" {
+		return "Proto"
+	}
+	return i.ProtocolField
+}
+
+type NetworkTransformThreatIntel struct {
+	Lists        []NetworkTransformThreatList `yaml:"lists,omitempty" json:"lists,omitempty" doc:"blocklist files, reloaded when modified"`
+	SrcIPField   string                       `yaml:"srcIPField,omitempty" json:"srcIPField,omitempty" doc:"source IP field (default: SrcAddr)"`
+	DstIPField   string                       `yaml:"dstIPField,omitempty" json:"dstIPField,omitempty" doc:"destination IP field (default: DstAddr)"`
+	DomainFields []string                     `yaml:"domainFields,omitempty" json:"domainFields,omitempty" doc:"fields with domain names, such as the host names from add_reverse_dns rule, matched against the domain indicators (optional)"`
+}
+
+func (i *NetworkTransformThreatIntel) GetSrcIPField() string {
+	if i == nil || i.SrcIPField == "" {
+		return "SrcAddr"
+	}
+	return i.SrcIPField
+}
+
+func (i *NetworkTransformThreatIntel) GetDstIPField() string {
+	if i == nil || i.DstIPField == "" {
+		return "DstAddr"
+	}
+	return i.DstIPField
+}
+
+const (
+	ThreatListFormatList = "list"
+	ThreatListFormatCSV  = "csv"
+	ThreatListFormatSTIX = "stix"
+)
+
+type NetworkTransformThreatList struct {
+	Name     string `yaml:"name,omitempty" json:"name,omitempty" doc:"name of the list, set in ThreatSource"`
+	File     string `yaml:"file,omitempty" json:"file,omitempty" doc:"path to the list file"`
+	Format   string `yaml:"format,omitempty" json:"format,omitempty" doc:"list (one IP, CIDR or domain per line), csv (indicator,category records) or stix (STIX 2.1 bundle of indicators) (default: stix for .json files, csv for .csv files, list otherwise)"`
+	Category string `yaml:"category,omitempty" json:"category,omitempty" doc:"category of the indicators that don't have one (optional)"`
+}
+
+func (l *NetworkTransformThreatList) GetFormat() string {
+	if l.Format != "" {
+		return l.Format
+	}
+	switch strings.ToLower(filepath.Ext(l.File)) {
+	case ".json":
+		return ThreatListFormatSTIX
+	case ".csv":
+		return ThreatListFormatCSV
+	}
+	return ThreatListFormatList
+}
+
+type NetworkTransformReverseDNS struct {
+	ResolverAddress string   `yaml:"resolverAddress,omitempty" json:"resolverAddress,omitempty" doc:"address (host:port) of the DNS server (optional, default: system resolver)"`
+	Timeout         Duration `yaml:"timeout,omitempty" json:"timeout,omitempty" doc:"maximum duration of each lookup (optional, default: 1s)"`
+	PositiveTTL     Duration `yaml:"positiveTTL,omitempty" json:"positiveTTL,omitempty" doc:"time to keep resolved host names in cache (optional, default: 10m)"`
+	NegativeTTL     Duration `yaml:"negativeTTL,omitempty" json:"negativeTTL,omitempty" doc:"time to keep failed resolutions in cache (optional, default: 1m)"`
+	MaxConcurrency  int      `yaml:"maxConcurrency,omitempty" json:"maxConcurrency,omitempty" doc:"maximum number of concurrent lookups (optional, default: 10)"`
+	QueueLength     int      `yaml:"queueLength,omitempty" json:"queueLength,omitempty" doc:"maximum number of pending lookups; IPs beyond it are not resolved (optional, default: 1000)"`
+}
+
+func (r *NetworkTransformReverseDNS) GetTimeout() time.Duration {
+	if r == nil || r.Timeout.Duration == 0 {
+		return time.Second
+	}
+	return r.Timeout.Duration
+}
+
+func (r *NetworkTransformReverseDNS) GetPositiveTTL() time.Duration {
+	if r == nil || r.PositiveTTL.Duration == 0 {
+		return 10 * time.Minute
+	}
+	return r.PositiveTTL.Duration
+}
+
+func (r *NetworkTransformReverseDNS) GetNegativeTTL() time.Duration {
+	if r == nil || r.NegativeTTL.Duration == 0 {
+		return time.Minute
+	}
+	return r.NegativeTTL.Duration
+}
+
+func (r *NetworkTransformReverseDNS) GetMaxConcurrency() int {
+	if r == nil || r.MaxConcurrency <= 0 {
+		return 10
+	}
+	return r.MaxConcurrency
+}
+
+func (r *NetworkTransformReverseDNS) GetQueueLength() int {
+	if r == nil || r.QueueLength <= 0 {
+		return 1000
+	}
+	return r.QueueLength
+}
+
 type NetworkTransformRules []NetworkTransformRule
 
 type NetworkTransformIPCategory struct {
 	CIDRs []string `yaml:"cidrs,omitempty" json:"cidrs,omitempty" doc:"list of CIDRs to match a category"`
 	Name  string   `yaml:"name,omitempty" json:"name,omitempty" doc:"name of the category"`
 }
+
+type NetworkTransformSubnetLabel struct {
+	CIDRs []string `yaml:"cidrs,omitempty" json:"cidrs,omitempty" doc:"list of CIDRs to match a label"`
+	Name  string   `yaml:"name,omitempty" json:"name,omitempty" doc:"name of the label"`
+}
@@ -100,10 +100,13 @@ type File struct {
 }
 
 type Transform struct {
-	Type    string                `yaml:"type" json:"type"`
-	Generic *api.TransformGeneric `yaml:"generic,omitempty" json:"generic,omitempty"`
-	Filter  *api.TransformFilter  `yaml:"filter,omitempty" json:"filter,omitempty"`
-	Network *api.TransformNetwork `yaml:"network,omitempty" json:"network,omitempty"`
+	Type      string                  `yaml:"type" json:"type"`
+	Generic   *api.TransformGeneric   `yaml:"generic,omitempty" json:"generic,omitempty"`
+	Filter    *api.TransformFilter    `yaml:"filter,omitempty" json:"filter,omitempty"`
+	Network   *api.TransformNetwork   `yaml:"network,omitempty" json:"network,omitempty"`
+	Anonymize *api.TransformAnonymize `yaml:"anonymize,omitempty" json:"anonymize,omitempty"`
+	Lookup    *api.TransformLookup    `yaml:"lookup,omitempty" json:"lookup,omitempty"`
+	Explode   *api.TransformExplode   `yaml:"explode,omitempty" json:"explode,omitempty"`
 }
 
 type Extract struct {
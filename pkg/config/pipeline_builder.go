@@ -120,6 +120,21 @@ func (b *PipelineBuilderStage) TransformNetwork(name string, nw api.TransformNet
 	return b.next(name, NewTransformNetworkParams(name, nw))
 }
 
+// TransformAnonymize chains the current stage with a TransformAnonymize stage and returns that new stage
+func (b *PipelineBuilderStage) TransformAnonymize(name string, anon api.TransformAnonymize) PipelineBuilderStage {
+	return b.next(name, NewTransformAnonymizeParams(name, anon))
+}
+
+// TransformLookup chains the current stage with a TransformLookup stage and returns that new stage
+func (b *PipelineBuilderStage) TransformLookup(name string, lookup api.TransformLookup) PipelineBuilderStage {
+	return b.next(name, NewTransformLookupParams(name, lookup))
+}
+
+// TransformExplode chains the current stage with a TransformExplode stage and returns that new stage
+func (b *PipelineBuilderStage) TransformExplode(name string, explode api.TransformExplode) PipelineBuilderStage {
+	return b.next(name, NewTransformExplodeParams(name, explode))
+}
+
 // ConnTrack chains the current stage with a ConnTrack stage and returns that new stage
 func (b *PipelineBuilderStage) ConnTrack(name string, ct api.ConnTrack) PipelineBuilderStage {
 	return b.next(name, NewConnTrackParams(name, ct))
@@ -49,6 +49,18 @@ func NewTransformNetworkParams(name string, nw api.TransformNetwork) StageParam
 	return StageParam{Name: name, Transform: &Transform{Type: api.NetworkType, Network: &nw}}
 }
 
+func NewTransformAnonymizeParams(name string, anon api.TransformAnonymize) StageParam {
+	return StageParam{Name: name, Transform: &Transform{Type: api.AnonymizeType, Anonymize: &anon}}
+}
+
+func NewTransformLookupParams(name string, lookup api.TransformLookup) StageParam {
+	return StageParam{Name: name, Transform: &Transform{Type: api.LookupType, Lookup: &lookup}}
+}
+
+func NewTransformExplodeParams(name string, explode api.TransformExplode) StageParam {
+	return StageParam{Name: name, Transform: &Transform{Type: api.ExplodeType, Explode: &explode}}
+}
+
 func NewConnTrackParams(name string, ct api.ConnTrack) StageParam {
 	return StageParam{Name: name, Extract: &Extract{Type: api.ConnTrackType, ConnTrack: &ct}}
 }
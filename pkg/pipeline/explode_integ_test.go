@@ -0,0 +1,81 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	test2 "github.com/mariomac/guara/pkg/test"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/ingest"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/write"
+	"github.com/netobserv/flowlogs-pipeline/pkg/test"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigExplode = `---
+pipeline:
+  - name: ingest_explode
+  - follows: ingest_explode
+    name: explode
+  - follows: explode
+    name: write_explode
+parameters:
+  - ingest:
+      type: fake
+    name: ingest_explode
+  - name: explode
+    transform:
+      type: explode
+      explode:
+        splits:
+          - vars: { dir: AB, a: Src, b: Dst }
+          - vars: { dir: BA, a: Dst, b: Src }
+        fields:
+          - { input: "Bytes_{dir}", output: Bytes }
+          - { input: "{a}Addr", output: SrcAddr }
+          - { input: "{b}Addr", output: DstAddr }
+  - name: write_explode
+    write:
+      type: fake
+`
+
+func TestExplode(t *testing.T) {
+	// This test runs a 3 stage pipeline (Ingester -> Explode -> Write), and checks that each ingested record
+	// is written once per direction
+	v, cfg := test.InitConfig(t, testConfigExplode)
+	require.NotNil(t, v)
+
+	mainPipeline, err := NewPipeline(cfg)
+	require.NoError(t, err)
+
+	go mainPipeline.Run()
+
+	in := mainPipeline.pipelineStages[0].Ingester.(*ingest.IngestFake).In
+	writer := mainPipeline.pipelineStages[2].Writer.(*write.WriteFake)
+
+	in <- config.GenericMap{"SrcAddr": "10.0.0.1", "DstAddr": "10.0.0.2", "Bytes_AB": 100, "Bytes_BA": 2000}
+
+	test2.Eventually(t, 15*time.Second, func(t require.TestingT) {
+		require.Equal(t, []config.GenericMap{
+			{"SrcAddr": "10.0.0.1", "DstAddr": "10.0.0.2", "Bytes": 100},
+			{"SrcAddr": "10.0.0.2", "DstAddr": "10.0.0.1", "Bytes": 2000},
+		}, writer.AllRecords())
+	}, test2.Interval(10*time.Millisecond))
+}
@@ -293,7 +293,11 @@ func (b *builder) getStageNode(pe *pipelineEntry, stageID string) (interface{},
 			b.opMetrics.CreateOutQueueSizeGauge(stageID, func() int { return len(out) })
 			for i := range in {
 				b.runMeasured(stageID, func() {
-					if transformed, ok := pe.Transformer.Transform(i); ok {
+					if multi, ok := pe.Transformer.(transform.MultiTransformer); ok {
+						for _, transformed := range multi.TransformMulti(i) {
+							out <- transformed
+						}
+					} else if transformed, ok := pe.Transformer.Transform(i); ok {
 						out <- transformed
 					}
 				})
@@ -367,11 +371,17 @@ func getTransformer(opMetrics *operational.Metrics, params config.StageParam) (t
 	var err error
 	switch params.Transform.Type {
 	case api.GenericType:
-		transformer, err = transform.NewTransformGeneric(params)
+		transformer, err = transform.NewTransformGeneric(opMetrics, params)
 	case api.FilterType:
 		transformer, err = transform.NewTransformFilter(params)
 	case api.NetworkType:
-		transformer, err = transform.NewTransformNetwork(params)
+		transformer, err = transform.NewTransformNetwork(opMetrics, params)
+	case api.AnonymizeType:
+		transformer, err = transform.NewTransformAnonymize(params)
+	case api.LookupType:
+		transformer, err = transform.NewTransformLookup(params)
+	case api.ExplodeType:
+		transformer, err = transform.NewTransformExplode(params)
 	case api.NoneType:
 		transformer, err = transform.NewTransformNone()
 	default:
@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package anonymize provides keyed, deterministic pseudonymization of IP addresses, MAC addresses and
+// strings: the same key always produces the same output, so that several replicas sharing the key
+// produce consistent datasets.
+package anonymize
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+)
+
+// KeySize is the size of the key: the first half is the AES key and the second half is used to
+// generate the Crypto-PAn pad
+const KeySize = 32
+
+// Anonymizer pseudonymizes values with a secret key
+type Anonymizer struct {
+	key   []byte
+	block cipher.Block
+	// pad is the encrypted second half of the key, filling the bits after the prefix of the IP
+	// being anonymized
+	pad [aes.BlockSize]byte
+}
+
+// LoadKey reads a key from a file, containing either the raw key or its hexadecimal encoding
+func LoadKey(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading anonymization key %q: %w", path, err)
+	}
+	if len(content) == KeySize {
+		return content, nil
+	}
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) == 2*KeySize {
+		if key, err := hex.DecodeString(string(trimmed)); err == nil {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid anonymization key %q: expected %d bytes, raw or hex-encoded", path, KeySize)
+}
+
+func NewAnonymizer(key []byte) (*Anonymizer, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid anonymization key size: expected %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key[:aes.BlockSize])
+	if err != nil {
+		return nil, err
+	}
+	a := &Anonymizer{key: key, block: block}
+	block.Encrypt(a.pad[:], key[aes.BlockSize:])
+	return a, nil
+}
+
+// IP applies the Crypto-PAn prefix-preserving anonymization: two addresses sharing a prefix of n bits
+// are anonymized into two addresses sharing a prefix of n bits too. IPv4 addresses give the same
+// results as the reference Crypto-PAn implementation, which is generalized to 128 bits for IPv6.
+func (a *Anonymizer) IP(ip net.IP) net.IP {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	var input, output [aes.BlockSize]byte
+	anonymized := make(net.IP, len(ip))
+	copy(anonymized, ip)
+	for pos := 0; pos < 8*len(ip); pos++ {
+		// the input block is made of the first pos bits of the IP, followed by the pad bits
+		input = a.pad
+		fullBytes := pos / 8
+		copy(input[:fullBytes], ip[:fullBytes])
+		if bits := pos % 8; bits > 0 {
+			mask := byte(0xff) << (8 - bits)
+			input[fullBytes] = (ip[fullBytes] & mask) | (a.pad[fullBytes] &^ mask)
+		}
+		a.block.Encrypt(output[:], input[:])
+		// the most significant bit of the output flips the IP bit at this position
+		anonymized[pos/8] ^= (output[0] >> 7) << (7 - pos%8)
+	}
+	return anonymized
+}
+
+// HashMAC replaces the MAC address by a keyed hash of the same length. The multicast bit is kept, while
+// the locally administered bit is set, since the result isn't a vendor-assigned address anymore.
+func (a *Anonymizer) HashMAC(mac net.HardwareAddr) net.HardwareAddr {
+	if len(mac) == 0 {
+		return mac
+	}
+	sum := a.hmac([]byte("mac"), mac)
+	hashed := make(net.HardwareAddr, len(mac))
+	copy(hashed, sum)
+	hashed[0] = (hashed[0] &^ 0x01) | (mac[0] & 0x01) | 0x02
+	return hashed
+}
+
+// TruncateMAC keeps the Organizationally Unique Identifier (the first 3 bytes) of the MAC address,
+// and zeroes the remaining bytes
+func TruncateMAC(mac net.HardwareAddr) net.HardwareAddr {
+	truncated := make(net.HardwareAddr, len(mac))
+	copy(truncated, mac[:min(3, len(mac))])
+	return truncated
+}
+
+// String returns the hexadecimal HMAC-SHA256 of the string, truncated to 128 bits
+func (a *Anonymizer) String(s string) string {
+	return hex.EncodeToString(a.hmac([]byte("string"), []byte(s))[:16])
+}
+
+// hmac computes the HMAC-SHA256 of the value, prefixed by a domain, so that different kinds of values
+// don't share their hashes
+func (a *Anonymizer) hmac(domain, value []byte) []byte {
+	h := hmac.New(sha256.New, a.key)
+	h.Write(domain)
+	h.Write([]byte{0})
+	h.Write(value)
+	return h.Sum(nil)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
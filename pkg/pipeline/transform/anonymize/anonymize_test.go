@@ -0,0 +1,142 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package anonymize
+
+import (
+	"encoding/hex"
+	"net"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// key from the Crypto-PAn reference implementation sample
+var referenceKey = []byte{21, 34, 23, 141, 51, 164, 207, 128, 19, 10, 91, 22, 73, 144, 125, 16,
+	216, 152, 143, 131, 121, 121, 101, 39, 98, 87, 76, 45, 42, 132, 34, 2}
+
+func TestIP_Reference(t *testing.T) {
+	a, err := NewAnonymizer(referenceKey)
+	require.NoError(t, err)
+	for original, expected := range map[string]string{
+		"128.11.68.132":   "135.242.180.132",
+		"129.118.74.4":    "134.136.186.123",
+		"130.132.252.244": "133.68.164.234",
+		"141.223.7.43":    "141.167.8.160",
+		"141.233.145.108": "141.129.237.235",
+		"152.163.225.39":  "151.140.114.167",
+		"156.29.3.236":    "147.225.12.42",
+		"165.247.96.84":   "162.9.99.234",
+		"166.107.77.190":  "160.132.178.185",
+		"192.102.249.13":  "252.138.62.131",
+	} {
+		require.Equal(t, expected, a.IP(net.ParseIP(original)).String(), original)
+	}
+}
+
+func commonPrefixLength(a, b net.IP) int {
+	for i := 0; i < 8*len(a); i++ {
+		mask := byte(0x80) >> (i % 8)
+		if a[i/8]&mask != b[i/8]&mask {
+			return i
+		}
+	}
+	return 8 * len(a)
+}
+
+func TestIP_PrefixPreserving(t *testing.T) {
+	a, err := NewAnonymizer(referenceKey)
+	require.NoError(t, err)
+	for _, pair := range [][2]string{
+		{"10.1.2.3", "10.1.2.200"},
+		{"10.1.2.3", "10.200.0.1"},
+		{"10.1.2.3", "192.168.0.1"},
+		{"2001:db8::1", "2001:db8::2"},
+		{"2001:db8:1::1", "2001:db8:ffff::1"},
+		{"2001:db8::1", "fd00::1"},
+	} {
+		ip1, ip2 := net.ParseIP(pair[0]), net.ParseIP(pair[1])
+		if ip1.To4() != nil {
+			ip1, ip2 = ip1.To4(), ip2.To4()
+		}
+		anon1, anon2 := a.IP(ip1), a.IP(ip2)
+		require.Equal(t, commonPrefixLength(ip1, ip2), commonPrefixLength(anon1, anon2), pair)
+		require.NotEqual(t, ip1.String(), anon1.String())
+	}
+	// deterministic, including between IPv4 representations
+	other, err := NewAnonymizer(referenceKey)
+	require.NoError(t, err)
+	require.Equal(t, a.IP(net.ParseIP("2001:db8::1")), other.IP(net.ParseIP("2001:db8::1")))
+	require.Equal(t, a.IP(net.ParseIP("10.1.2.3").To4()), other.IP(net.ParseIP("10.1.2.3")))
+}
+
+func TestMAC(t *testing.T) {
+	a, err := NewAnonymizer(referenceKey)
+	require.NoError(t, err)
+	mac, _ := net.ParseMAC("00:1b:21:3a:4b:5c")
+	hashed := a.HashMAC(mac)
+	require.Len(t, hashed, 6)
+	require.NotEqual(t, mac, hashed)
+	require.Equal(t, hashed, a.HashMAC(mac))
+	// locally administered, unicast
+	require.Equal(t, byte(0x02), hashed[0]&0x03)
+	multicast, _ := net.ParseMAC("01:00:5e:00:00:fb")
+	require.Equal(t, byte(0x03), a.HashMAC(multicast)[0]&0x03)
+
+	require.Equal(t, "00:1b:21:00:00:00", TruncateMAC(mac).String())
+}
+
+func TestString(t *testing.T) {
+	a, err := NewAnonymizer(referenceKey)
+	require.NoError(t, err)
+	hashed := a.String("web-5d8f7-abcde")
+	require.Len(t, hashed, 32)
+	require.Equal(t, hashed, a.String("web-5d8f7-abcde"))
+	require.NotEqual(t, hashed, a.String("web-5d8f7-fghij"))
+
+	otherKey := append([]byte{}, referenceKey...)
+	otherKey[0]++
+	b, err := NewAnonymizer(otherKey)
+	require.NoError(t, err)
+	require.NotEqual(t, hashed, b.String("web-5d8f7-abcde"))
+}
+
+func TestLoadKey(t *testing.T) {
+	dir := t.TempDir()
+	raw := path.Join(dir, "raw.key")
+	require.NoError(t, os.WriteFile(raw, referenceKey, 0600))
+	key, err := LoadKey(raw)
+	require.NoError(t, err)
+	require.Equal(t, referenceKey, key)
+
+	encoded := path.Join(dir, "hex.key")
+	require.NoError(t, os.WriteFile(encoded, []byte(hex.EncodeToString(referenceKey)+"\n"), 0600))
+	key, err = LoadKey(encoded)
+	require.NoError(t, err)
+	require.Equal(t, referenceKey, key)
+
+	short := path.Join(dir, "short.key")
+	require.NoError(t, os.WriteFile(short, []byte("secret"), 0600))
+	_, err = LoadKey(short)
+	require.Error(t, err)
+	_, err = LoadKey(path.Join(dir, "missing.key"))
+	require.Error(t, err)
+	_, err = NewAnonymizer([]byte("secret"))
+	require.Error(t, err)
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package asn
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/ip2location/ip2location-go/v9"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/ipdb"
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// ip2locationASNType is the IP2Location database type that provides the ASN and AS columns (DB26)
+const ip2locationASNType = "26"
+
+type Info struct {
+	ASN          uint32
+	Organization string
+}
+
+// DB provides the Autonomous System information of IP addresses from a local database file, which
+// can be either a MaxMind GeoLite2-ASN database (.mmdb extension) or an IP2Location BIN database
+// providing the ASN and AS columns (any other extension).
+type DB struct {
+	*ipdb.DB
+}
+
+// OpenDB opens the provided database file
+func OpenDB(path string) (*DB, error) {
+	db, err := ipdb.Open(path, ipdb.Options{Kind: "ASN", Validate: validate})
+	if err != nil {
+		return nil, err
+	}
+	return &DB{DB: db}, nil
+}
+
+func validate(r *ipdb.Reader) error {
+	if r.IP2Location != nil && r.IP2Location.PackageVersion() != ip2locationASNType {
+		return fmt.Errorf("IP2Location database doesn't provide ASN information (type DB%s)", r.IP2Location.PackageVersion())
+	}
+	return nil
+}
+
+// Lookup returns the Autonomous System information of the provided IP, or nil if it is not found
+func (db *DB) Lookup(ip net.IP) (*Info, error) {
+	r := db.RLock()
+	defer db.RUnlock()
+	if r.MaxMind != nil {
+		return lookupMaxMind(r.MaxMind, ip)
+	}
+	return lookupIP2Location(r.IP2Location, ip)
+}
+
+type maxMindRecord struct {
+	AutonomousSystemNumber       uint32 `maxminddb:"autonomous_system_number"`
+	AutonomousSystemOrganization string `maxminddb:"autonomous_system_organization"`
+}
+
+func lookupMaxMind(db *maxminddb.Reader, ip net.IP) (*Info, error) {
+	var record maxMindRecord
+	if _, ok, err := db.LookupNetwork(ip, &record); err != nil || !ok || record.AutonomousSystemNumber == 0 {
+		return nil, err
+	}
+	return &Info{ASN: record.AutonomousSystemNumber, Organization: record.AutonomousSystemOrganization}, nil
+}
+
+func lookupIP2Location(db *ip2location.DB, ip net.IP) (*Info, error) {
+	strIP := ip.String()
+	record, err := db.Get_asn(strIP)
+	if err != nil {
+		return nil, err
+	}
+	// unknown ranges have "-" as ASN, and IPs out of any range have an error message
+	asn, err := strconv.ParseUint(record.Asn, 10, 32)
+	if err != nil || asn == 0 {
+		return nil, nil
+	}
+	record, err = db.Get_as(strIP)
+	if err != nil {
+		return nil, err
+	}
+	return &Info{ASN: uint32(asn), Organization: record.As}, nil
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package asn
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	for _, file := range []string{"GeoLite2-ASN-test.mmdb", "IP2LOCATION-ASN-test.BIN"} {
+		t.Run(file, func(t *testing.T) {
+			db, err := OpenDB(filepath.Join("testdata", file))
+			require.NoError(t, err)
+			defer db.Close()
+
+			info, err := db.Lookup(net.ParseIP("8.8.8.8"))
+			require.NoError(t, err)
+			require.Equal(t, &Info{ASN: 15169, Organization: "GOOGLE"}, info)
+
+			info, err = db.Lookup(net.ParseIP("1.1.1.1"))
+			require.NoError(t, err)
+			require.Equal(t, &Info{ASN: 13335, Organization: "CLOUDFLARENET"}, info)
+
+			info, err = db.Lookup(net.ParseIP("10.0.0.1"))
+			require.NoError(t, err)
+			require.Nil(t, info)
+		})
+	}
+}
+
+func TestLookupIPv6(t *testing.T) {
+	db, err := OpenDB(filepath.Join("testdata", "GeoLite2-ASN-test.mmdb"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	info, err := db.Lookup(net.ParseIP("2001:4860:4860::8888"))
+	require.NoError(t, err)
+	require.Equal(t, &Info{ASN: 15169, Organization: "GOOGLE"}, info)
+}
+
+func TestOpenErrors(t *testing.T) {
+	_, err := OpenDB(filepath.Join("testdata", "not-found.mmdb"))
+	require.Error(t, err)
+
+	// IP2Location database without ASN information
+	_, err = OpenDB(filepath.Join("testdata", "IP2LOCATION-DB1-test.BIN"))
+	require.Error(t, err)
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "asn.mmdb")
+	copyFile(t, filepath.Join("testdata", "GeoLite2-ASN-test.mmdb"), path)
+	db, err := OpenDB(path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// invalid content: previous database is kept
+	replaceFile(t, path, []byte("invalid"))
+	require.Error(t, db.Reload())
+	info, err := db.Lookup(net.ParseIP("1.1.1.1"))
+	require.NoError(t, err)
+	require.Equal(t, &Info{ASN: 13335, Organization: "CLOUDFLARENET"}, info)
+
+	content, err := os.ReadFile(filepath.Join("testdata", "GeoLite2-ASN-test-updated.mmdb"))
+	require.NoError(t, err)
+	replaceFile(t, path, content)
+	require.NoError(t, db.Reload())
+	info, err = db.Lookup(net.ParseIP("8.8.8.8"))
+	require.NoError(t, err)
+	require.Equal(t, &Info{ASN: 15169, Organization: "GOOGLE LLC"}, info)
+	info, err = db.Lookup(net.ParseIP("1.1.1.1"))
+	require.NoError(t, err)
+	require.Nil(t, info)
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	content, err := os.ReadFile(src)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dst, content, 0600))
+}
+
+// replaceFile atomically replaces the content of the database file, as the database is read from the file
+func replaceFile(t *testing.T, path string, content []byte) {
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, content, 0600))
+	require.NoError(t, os.Rename(tmp, path))
+}
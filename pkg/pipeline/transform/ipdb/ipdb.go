@@ -0,0 +1,218 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+// Package ipdb loads the IP databases used by the enrichment rules, which are either MaxMind databases
+// (.mmdb extension) or IP2Location BIN databases (any other extension), and reloads them while they are queried.
+package ipdb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/ip2location/ip2location-go/v9"
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// Reader is an open database. Exactly one of MaxMind and IP2Location is set.
+type Reader struct {
+	MaxMind     *maxminddb.Reader
+	IP2Location *ip2location.DB
+	// mapping is the memory-mapped MaxMind database file, which the MaxMind reader reads from
+	mapping []byte
+}
+
+func (r *Reader) close() {
+	if r.MaxMind != nil {
+		_ = r.MaxMind.Close()
+	}
+	if r.mapping != nil {
+		_ = syscall.Munmap(r.mapping)
+		r.mapping = nil
+	}
+	if r.IP2Location != nil {
+		r.IP2Location.Close()
+	}
+}
+
+type Options struct {
+	// Kind describes the database in error messages, e.g. "ASN"
+	Kind string
+	// ChecksumPath is the path of a file, following the sha256sum output format, against which the SHA-256
+	// checksum of the database is verified (optional)
+	ChecksumPath string
+	// Validate checks that the database provides the expected information (optional)
+	Validate func(r *Reader) error
+}
+
+// DB is a database file that can be reloaded while it is being queried
+type DB struct {
+	path string
+	opts Options
+	// mutex prevents closing a reader that is being queried during reloads
+	mutex  sync.RWMutex
+	reader *Reader
+}
+
+// Open loads the provided database file
+func Open(path string, opts Options) (*DB, error) {
+	r, err := load(path, &opts)
+	if err != nil {
+		return nil, err
+	}
+	return &DB{path: path, opts: opts, reader: r}, nil
+}
+
+// FromReader creates a DB from an already loaded reader, which can't be reloaded
+func FromReader(r *Reader) *DB {
+	return &DB{reader: r}
+}
+
+// Reload reopens the database file. The previous content is kept in case of error.
+func (db *DB) Reload() error {
+	r, err := load(db.path, &db.opts)
+	if err != nil {
+		return err
+	}
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	db.reader.close()
+	db.reader = r
+	return nil
+}
+
+// RLock returns the current reader, which remains open until RUnlock is called
+func (db *DB) RLock() *Reader {
+	db.mutex.RLock()
+	return db.reader
+}
+
+func (db *DB) RUnlock() {
+	db.mutex.RUnlock()
+}
+
+func (db *DB) Close() {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	db.reader.close()
+}
+
+// checksumVerified is called once the checksum of the database file is verified, for testing purposes
+var checksumVerified = func(string) {}
+
+// load opens the database file. The readers aren't loaded in memory: MaxMind databases are memory-mapped and
+// IP2Location databases are read from the file, so the file must be replaced atomically (e.g. renamed) rather
+// than modified in place while it is being used, until it is reloaded. The checksum is verified on the same
+// open file as the one that is loaded, so that a file replaced in the meantime is never loaded unverified.
+func load(path string, opts *Options) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s database %q: %w", opts.Kind, path, err)
+	}
+	var r Reader
+	if strings.EqualFold(filepath.Ext(path), ".mmdb") {
+		err = loadMaxMind(&r, file, path, opts)
+		// the memory mapping is kept after the file is closed
+		_ = file.Close()
+	} else if err = loadIP2Location(&r, file, path, opts); err != nil {
+		_ = file.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if opts.Validate != nil {
+		if err := opts.Validate(&r); err != nil {
+			r.close()
+			return nil, fmt.Errorf("%s database %q: %w", opts.Kind, path, err)
+		}
+	}
+	return &r, nil
+}
+
+func loadMaxMind(r *Reader, file *os.File, path string, opts *Options) error {
+	stat, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("reading %s database %q: %w", opts.Kind, path, err)
+	}
+	mapping, err := syscall.Mmap(int(file.Fd()), 0, int(stat.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return fmt.Errorf("mapping %s database %q: %w", opts.Kind, path, err)
+	}
+	if opts.ChecksumPath != "" {
+		if err := verifyChecksum(bytes.NewReader(mapping), opts.ChecksumPath); err != nil {
+			_ = syscall.Munmap(mapping)
+			return fmt.Errorf("verifying %s database %q: %w", opts.Kind, path, err)
+		}
+		checksumVerified(path)
+	}
+	if r.MaxMind, err = maxminddb.FromBytes(mapping); err != nil {
+		_ = syscall.Munmap(mapping)
+		return fmt.Errorf("opening MaxMind %s database %q: %w", opts.Kind, path, err)
+	}
+	r.mapping = mapping
+	return nil
+}
+
+func loadIP2Location(r *Reader, file *os.File, path string, opts *Options) error {
+	if opts.ChecksumPath != "" {
+		if err := verifyChecksum(file, opts.ChecksumPath); err != nil {
+			return fmt.Errorf("verifying %s database %q: %w", opts.Kind, path, err)
+		}
+		checksumVerified(path)
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("reading %s database %q: %w", opts.Kind, path, err)
+		}
+	}
+	var err error
+	if r.IP2Location, err = ip2location.OpenDBWithReader(file); err != nil {
+		return fmt.Errorf("opening IP2Location %s database %q: %w", opts.Kind, path, err)
+	}
+	return nil
+}
+
+// verifyChecksum computes the SHA-256 checksum of the database by streaming it
+func verifyChecksum(database io.Reader, checksumPath string) error {
+	checksumContent, err := os.ReadFile(checksumPath)
+	if err != nil {
+		return fmt.Errorf("reading checksum file %q: %w", checksumPath, err)
+	}
+	// sha256sum format: "<checksum>  <file name>"
+	fields := strings.Fields(string(checksumContent))
+	if len(fields) == 0 {
+		return fmt.Errorf("checksum file %q is empty", checksumPath)
+	}
+	expected, err := hex.DecodeString(fields[0])
+	if err != nil || len(expected) != sha256.Size {
+		return fmt.Errorf("checksum file %q doesn't contain a valid SHA-256 checksum", checksumPath)
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, database); err != nil {
+		return fmt.Errorf("computing checksum: %w", err)
+	}
+	actual := hash.Sum(nil)
+	if !bytes.Equal(expected, actual) {
+		return fmt.Errorf("SHA-256 checksum mismatch: expected %s, got %x", fields[0], actual)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package ipdb
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testMaxMindDB     = "../asn/testdata/GeoLite2-ASN-test.mmdb"
+	testIP2LocationDB = "../asn/testdata/IP2LOCATION-ASN-test.BIN"
+)
+
+func TestOpen(t *testing.T) {
+	db, err := Open(testMaxMindDB, Options{Kind: "test"})
+	require.NoError(t, err)
+	r := db.RLock()
+	require.NotNil(t, r.MaxMind)
+	require.Nil(t, r.IP2Location)
+	db.RUnlock()
+	db.Close()
+
+	db, err = Open(testIP2LocationDB, Options{Kind: "test"})
+	require.NoError(t, err)
+	r = db.RLock()
+	require.Nil(t, r.MaxMind)
+	require.NotNil(t, r.IP2Location)
+	db.RUnlock()
+	db.Close()
+
+	_, err = Open("testdata/missing.mmdb", Options{Kind: "test"})
+	require.ErrorContains(t, err, "reading test database")
+	_, err = Open(testMaxMindDB, Options{Kind: "test", Validate: func(*Reader) error { return errors.New("invalid") }})
+	require.ErrorContains(t, err, "invalid")
+}
+
+func TestOpen_Checksum(t *testing.T) {
+	content, err := os.ReadFile(testMaxMindDB)
+	require.NoError(t, err)
+	checksumPath := filepath.Join(t.TempDir(), "sha256sum")
+
+	require.NoError(t, os.WriteFile(checksumPath, []byte(fmt.Sprintf("%x  test.mmdb\n", sha256.Sum256(content))), 0600))
+	db, err := Open(testMaxMindDB, Options{ChecksumPath: checksumPath})
+	require.NoError(t, err)
+	db.Close()
+
+	require.NoError(t, os.WriteFile(checksumPath, []byte(fmt.Sprintf("%x\n", sha256.Sum256([]byte("other")))), 0600))
+	_, err = Open(testMaxMindDB, Options{ChecksumPath: checksumPath})
+	require.ErrorContains(t, err, "checksum mismatch")
+}
+
+func TestOpen_ChecksumReplacedFile(t *testing.T) {
+	for _, source := range []string{testMaxMindDB, testIP2LocationDB} {
+		content, err := os.ReadFile(source)
+		require.NoError(t, err)
+		dir := t.TempDir()
+		path := filepath.Join(dir, filepath.Base(source))
+		require.NoError(t, os.WriteFile(path, content, 0600))
+		checksumPath := filepath.Join(dir, "sha256sum")
+		require.NoError(t, os.WriteFile(checksumPath, []byte(fmt.Sprintf("%x\n", sha256.Sum256(content))), 0600))
+
+		// the file is replaced by an unverified one once the checksum is verified: the verified content is loaded
+		checksumVerified = func(string) { replaceFile(t, path, []byte("unverified")) }
+		db, err := Open(path, Options{ChecksumPath: checksumPath})
+		checksumVerified = func(string) {}
+		require.NoError(t, err, source)
+		r := db.RLock()
+		require.True(t, r.MaxMind != nil || r.IP2Location != nil)
+		db.RUnlock()
+		db.Close()
+	}
+}
+
+func TestReload(t *testing.T) {
+	content, err := os.ReadFile(testMaxMindDB)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "test.mmdb")
+	require.NoError(t, os.WriteFile(path, content, 0600))
+	db, err := Open(path, Options{})
+	require.NoError(t, err)
+	defer db.Close()
+	previous := db.RLock()
+	db.RUnlock()
+
+	// the previous content is kept in case of error
+	replaceFile(t, path, []byte("invalid"))
+	require.Error(t, db.Reload())
+	require.Same(t, previous, db.RLock())
+	db.RUnlock()
+
+	replaceFile(t, path, content)
+	require.NoError(t, db.Reload())
+	require.NotSame(t, previous, db.RLock())
+	db.RUnlock()
+}
+
+// replaceFile atomically replaces the content of the database file, as the databases are read from the file
+func replaceFile(t *testing.T, path string, content []byte) {
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, content, 0600))
+	require.NoError(t, os.Rename(tmp, path))
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Clusters looks up IPs in several named clusters, each one with its own informers or inventory.
+// The cluster is chosen from the value of a record field.
+type Clusters struct {
+	clusters []*cluster
+	// defaultCluster is used when no cluster matches the field value. It can be nil.
+	defaultCluster *cluster
+	// withCIDRs tells whether any cluster is selected by CIDRs, so that field values are only parsed as IPs then
+	withCIDRs bool
+}
+
+type cluster struct {
+	name           string
+	kubeConfigPath string
+	data           kubeDataInterface
+	// policies is only set when NetworkPolicies are evaluated
+	policies *PolicyEvaluator
+	values   map[string]struct{}
+	cidrs    []*net.IPNet
+}
+
+// NewClusters initializes the data of all the clusters from the configuration
+func NewClusters(cfg *api.NetworkTransformKubernetes, reloadPeriod time.Duration) (*Clusters, error) {
+	if cfg == nil || len(cfg.Clusters) == 0 {
+		return nil, errors.New("no Kubernetes clusters configured")
+	}
+	if len(cfg.Clusters) > 1 && cfg.ClusterField == "" {
+		return nil, errors.New("a cluster field is required to choose between several Kubernetes clusters")
+	}
+	c := &Clusters{}
+	names := map[string]struct{}{}
+	for i := range cfg.Clusters {
+		clusterCfg := &cfg.Clusters[i]
+		if clusterCfg.Name == "" {
+			return nil, fmt.Errorf("missing name for Kubernetes cluster #%d", i)
+		}
+		if _, ok := names[clusterCfg.Name]; ok {
+			return nil, fmt.Errorf("duplicate Kubernetes cluster name %q", clusterCfg.Name)
+		}
+		names[clusterCfg.Name] = struct{}{}
+
+		cl := &cluster{name: clusterCfg.Name, kubeConfigPath: clusterCfg.KubeConfigPath, values: map[string]struct{}{}}
+		for _, value := range clusterCfg.Values {
+			cl.values[value] = struct{}{}
+		}
+		for _, cidr := range clusterCfg.CIDRs {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR %q for Kubernetes cluster %q: %w", cidr, clusterCfg.Name, err)
+			}
+			cl.cidrs = append(cl.cidrs, ipNet)
+			c.withCIDRs = true
+		}
+		if len(cl.values) == 0 && len(cl.cidrs) == 0 {
+			if c.defaultCluster != nil {
+				return nil, fmt.Errorf("only one default Kubernetes cluster, without values nor CIDRs, is allowed: found %q and %q",
+					c.defaultCluster.name, cl.name)
+			}
+			c.defaultCluster = cl
+		}
+
+		// all the clusters share the same enrichment settings
+		dataCfg := *cfg
+		dataCfg.Clusters = nil
+		dataCfg.InventoryFile = clusterCfg.InventoryFile
+		if clusterCfg.InventoryFile != "" {
+			cl.data = NewInventoryData(reloadPeriod)
+		} else {
+			cl.data = &KubeData{}
+		}
+		if err := cl.data.InitFromConfig(clusterCfg.KubeConfigPath, &dataCfg); err != nil {
+			return nil, fmt.Errorf("can't initialize Kubernetes cluster %q: %w", clusterCfg.Name, err)
+		}
+		c.clusters = append(c.clusters, cl)
+	}
+	return c, nil
+}
+
+// GetInfo looks up the IP at the given time in the cluster chosen by the cluster field value,
+// and returns the cluster name
+func (c *Clusters) GetInfo(fieldValue interface{}, ip string, timestamp time.Time) (*Info, string, error) {
+	cl := c.selectCluster(fieldValue)
+	if cl == nil {
+		return nil, "", fmt.Errorf("no Kubernetes cluster matches %v", fieldValue)
+	}
+	info, err := cl.data.GetInfo(ip, timestamp)
+	return info, cl.name, err
+}
+
+// GetServiceEndpoints relates the IP, port and protocol to Services in the cluster chosen by the cluster field value
+func (c *Clusters) GetServiceEndpoints(fieldValue interface{}, ip string, port int, protocol string) (*ServiceEndpoints, error) {
+	cl := c.selectCluster(fieldValue)
+	if cl == nil {
+		return nil, fmt.Errorf("no Kubernetes cluster matches %v", fieldValue)
+	}
+	return cl.data.GetServiceEndpoints(ip, port, protocol)
+}
+
+// InitPolicies watches the NetworkPolicies of all the clusters. They are always read from the live cluster,
+// even when its Pods are read from an inventory file.
+func (c *Clusters) InitPolicies() error {
+	for _, cl := range c.clusters {
+		kubeConfig, err := LoadConfig(cl.kubeConfigPath)
+		if err != nil {
+			return fmt.Errorf("can't load configuration of Kubernetes cluster %q: %w", cl.name, err)
+		}
+		client, err := kubernetes.NewForConfig(kubeConfig)
+		if err != nil {
+			return fmt.Errorf("can't create client for Kubernetes cluster %q: %w", cl.name, err)
+		}
+		if cl.policies, err = NewPolicyEvaluator(client, cl.data); err != nil {
+			return fmt.Errorf("can't watch NetworkPolicies of Kubernetes cluster %q: %w", cl.name, err)
+		}
+	}
+	return nil
+}
+
+// EvaluatePolicies evaluates a flow against the NetworkPolicies of the cluster chosen by the cluster field value.
+// InitPolicies must have been called before.
+func (c *Clusters) EvaluatePolicies(fieldValue interface{}, srcIP, dstIP string, dstPort int, protocol string, timestamp time.Time) (*PolicyResult, error) {
+	cl := c.selectCluster(fieldValue)
+	if cl == nil {
+		return nil, fmt.Errorf("no Kubernetes cluster matches %v", fieldValue)
+	}
+	return cl.policies.Evaluate(srcIP, dstIP, dstPort, protocol, timestamp), nil
+}
+
+func (c *Clusters) selectCluster(fieldValue interface{}) *cluster {
+	if fieldValue == nil {
+		return c.defaultCluster
+	}
+	value := clusterFieldString(fieldValue)
+	var ip net.IP
+	if c.withCIDRs {
+		ip = net.ParseIP(value)
+	}
+	for _, cl := range c.clusters {
+		if _, ok := cl.values[value]; ok {
+			return cl
+		}
+		if ip != nil {
+			for _, cidr := range cl.cidrs {
+				if cidr.Contains(ip) {
+					return cl
+				}
+			}
+		}
+	}
+	return c.defaultCluster
+}
+
+// clusterFieldString formats the cluster field value as the %v verb does, without allocating for strings
+// and common numbers
+func clusterFieldString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package kubernetes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func writeInventory(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "inventory.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestClusters(t *testing.T) {
+	// the same IP is used in both clusters
+	east := writeInventory(t, "pods:\n  - name: pod-east\n    namespace: ns\n    ips: [10.128.0.5]\n")
+	west := writeInventory(t, "pods:\n  - name: pod-west\n    namespace: ns\n    ips: [10.128.0.5]\n")
+	other := writeInventory(t, "pods:\n  - name: pod-other\n    namespace: ns\n    ips: [10.128.0.5]\n")
+	clusters, err := NewClusters(&api.NetworkTransformKubernetes{
+		ClusterField: "AgentIP",
+		Clusters: []api.NetworkTransformKubeCluster{
+			{Name: "east", InventoryFile: east, CIDRs: []string{"10.0.0.0/16"}, Values: []string{"flows-east"}},
+			{Name: "west", InventoryFile: west, CIDRs: []string{"10.1.0.0/16", "fd00::/8"}},
+			{Name: "other", InventoryFile: other},
+		},
+	}, time.Minute)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		fieldValue interface{}
+		cluster    string
+		podName    string
+	}{
+		{fieldValue: "10.0.3.4", cluster: "east", podName: "pod-east"},
+		{fieldValue: "flows-east", cluster: "east", podName: "pod-east"},
+		{fieldValue: "10.1.3.4", cluster: "west", podName: "pod-west"},
+		{fieldValue: "fd00::1", cluster: "west", podName: "pod-west"},
+		{fieldValue: "192.168.0.1", cluster: "other", podName: "pod-other"},
+		{fieldValue: nil, cluster: "other", podName: "pod-other"},
+	} {
+		info, cluster, err := clusters.GetInfo(tc.fieldValue, "10.128.0.5", time.Time{})
+		require.NoError(t, err, tc.fieldValue)
+		require.Equal(t, tc.cluster, cluster, tc.fieldValue)
+		require.Equal(t, tc.podName, info.Name, tc.fieldValue)
+	}
+
+	_, cluster, err := clusters.GetInfo("10.0.3.4", "10.128.0.6", time.Time{})
+	require.Error(t, err)
+	require.Equal(t, "east", cluster)
+}
+
+func TestClusters_Policies(t *testing.T) {
+	// the same IP is a Pod in both clusters, which only have a policy in the east one
+	east := writeInventory(t, "pods:\n  - name: pod-east\n    namespace: ns\n    ips: [10.128.0.5]\n")
+	west := writeInventory(t, "pods:\n  - name: pod-west\n    namespace: ns\n    ips: [10.128.0.5]\n")
+	clusters, err := NewClusters(&api.NetworkTransformKubernetes{
+		ClusterField: "Topic",
+		Clusters: []api.NetworkTransformKubeCluster{
+			{Name: "east", InventoryFile: east, Values: []string{"flows-east"}},
+			{Name: "west", InventoryFile: west, Values: []string{"flows-west"}},
+		},
+	}, time.Minute)
+	require.NoError(t, err)
+	for _, cl := range clusters.clusters {
+		var objects []runtime.Object
+		if cl.name == "east" {
+			objects = append(objects, &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "deny-all", Namespace: "ns"}})
+		}
+		cl.policies, err = NewPolicyEvaluator(fake.NewSimpleClientset(objects...), cl.data)
+		require.NoError(t, err)
+		defer close(cl.policies.stopChan)
+	}
+
+	result, err := clusters.EvaluatePolicies("flows-east", "1.2.3.4", "10.128.0.5", 80, "TCP", time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, &PolicyResult{Verdict: PolicyDeny, Policies: []string{"ns/deny-all"}}, result)
+	result, err = clusters.EvaluatePolicies("flows-west", "1.2.3.4", "10.128.0.5", 80, "TCP", time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, &PolicyResult{Verdict: PolicyAllow}, result)
+	_, err = clusters.EvaluatePolicies("flows-north", "1.2.3.4", "10.128.0.5", 80, "TCP", time.Time{})
+	require.Error(t, err)
+}
+
+func TestClusters_NoDefault(t *testing.T) {
+	east := writeInventory(t, "pods:\n  - name: pod-east\n    namespace: ns\n    ips: [10.128.0.5]\n")
+	clusters, err := NewClusters(&api.NetworkTransformKubernetes{
+		ClusterField: "Topic",
+		Clusters:     []api.NetworkTransformKubeCluster{{Name: "east", InventoryFile: east, Values: []string{"flows-east"}}},
+	}, time.Minute)
+	require.NoError(t, err)
+	_, _, err = clusters.GetInfo("flows-west", "10.128.0.5", time.Time{})
+	require.Error(t, err)
+}
+
+func TestClusters_Errors(t *testing.T) {
+	inventory := writeInventory(t, "pods: []\n")
+	for name, cfg := range map[string]*api.NetworkTransformKubernetes{
+		"no clusters": nil,
+		"missing cluster field": {Clusters: []api.NetworkTransformKubeCluster{
+			{Name: "a", InventoryFile: inventory}, {Name: "b", InventoryFile: inventory, Values: []string{"b"}},
+		}},
+		"missing name": {Clusters: []api.NetworkTransformKubeCluster{{InventoryFile: inventory}}},
+		"duplicate name": {ClusterField: "f", Clusters: []api.NetworkTransformKubeCluster{
+			{Name: "a", InventoryFile: inventory}, {Name: "a", InventoryFile: inventory, Values: []string{"b"}},
+		}},
+		"several defaults": {ClusterField: "f", Clusters: []api.NetworkTransformKubeCluster{
+			{Name: "a", InventoryFile: inventory}, {Name: "b", InventoryFile: inventory},
+		}},
+		"invalid CIDR": {ClusterField: "f", Clusters: []api.NetworkTransformKubeCluster{
+			{Name: "a", InventoryFile: inventory, CIDRs: []string{"10.0.0.0"}},
+		}},
+		"invalid inventory": {ClusterField: "f", Clusters: []api.NetworkTransformKubeCluster{
+			{Name: "a", InventoryFile: inventory + ".notfound"},
+		}},
+	} {
+		_, err := NewClusters(cfg, time.Minute)
+		require.Error(t, err, name)
+	}
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cni
+
+import v1 "k8s.io/api/core/v1"
+
+// Calico node annotations holding the addresses of its tunnel interfaces
+var calicoTunnelAnnotations = []string{
+	"projectcalico.org/IPv4IPIPTunnelAddr",
+	"projectcalico.org/IPv4VXLANTunnelAddr",
+	"projectcalico.org/IPv6VXLANTunnelAddr",
+	"projectcalico.org/IPv4WireguardInterfaceAddr",
+	"projectcalico.org/IPv6WireguardInterfaceAddr",
+}
+
+type calico struct{}
+
+// NodeIPs returns the IPs of the Calico tunnel interfaces (IPIP, VXLAN and WireGuard)
+func (calico) NodeIPs(node *v1.Node) ([]string, error) {
+	return annotationIPs(node.Annotations, calicoTunnelAnnotations...)
+}
@@ -0,0 +1,37 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cni
+
+import v1 "k8s.io/api/core/v1"
+
+// Cilium node annotations holding the addresses of the cilium_host interface, which is used
+// as the source of the traffic from the node to the Pods and through the tunnels. Older
+// Cilium versions use the io.cilium.network prefix.
+var ciliumHostAnnotations = []string{
+	"network.cilium.io/ipv4-cilium-host",
+	"network.cilium.io/ipv6-cilium-host",
+	"io.cilium.network.ipv4-cilium-host",
+	"io.cilium.network.ipv6-cilium-host",
+}
+
+type cilium struct{}
+
+// NodeIPs returns the IPs of the cilium_host interface
+func (cilium) NodeIPs(node *v1.Node) ([]string, error) {
+	return annotationIPs(node.Annotations, ciliumHostAnnotations...)
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cni
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Plugin finds the CNI-specific IPs of a Node, such as tunnel or management interface IPs, so that
+// the traffic using them can be attributed to the Node. Plugins must work regardless of whether
+// their CNI is installed: they return no IP when the information they rely on is missing.
+type Plugin interface {
+	NodeIPs(node *v1.Node) ([]string, error)
+}
+
+var plugins = map[string]Plugin{
+	"ovn-kubernetes": ovnKubernetes{},
+	"calico":         calico{},
+	"cilium":         cilium{},
+}
+
+// GetPlugins returns the plugins with the provided names, or all the plugins if no name is provided
+func GetPlugins(names []string) ([]Plugin, error) {
+	if len(names) == 0 {
+		names = make([]string, 0, len(plugins))
+		for name := range plugins {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	selected := make([]Plugin, 0, len(names))
+	for _, name := range names {
+		plugin, ok := plugins[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown CNI plugin %q", name)
+		}
+		selected = append(selected, plugin)
+	}
+	return selected, nil
+}
+
+// AddNodeIPs adds the IPs found by the plugins to the provided Node IPs
+func AddNodeIPs(ips []string, node *v1.Node, plugins []Plugin) []string {
+	for _, plugin := range plugins {
+		pluginIPs, err := plugin.NodeIPs(node)
+		if err != nil {
+			// Log the error as Info, do not block other ips indexing
+			log.Infof("failed to index CNI IPs for node %s: %v", node.Name, err)
+			continue
+		}
+		ips = append(ips, pluginIPs...)
+	}
+	return ips
+}
+
+// annotationIPs returns the IPs from the provided annotations, which can be written in CIDR notation
+func annotationIPs(annotations map[string]string, keys ...string) ([]string, error) {
+	var ips []string
+	for _, key := range keys {
+		value, ok := annotations[key]
+		if !ok || value == "" {
+			continue
+		}
+		ip := net.ParseIP(value)
+		if ip == nil {
+			var err error
+			if ip, _, err = net.ParseCIDR(value); err != nil {
+				return nil, fmt.Errorf("cannot read annotation %s: invalid IP %q", key, value)
+			}
+		}
+		if !contains(ips, ip.String()) {
+			ips = append(ips, ip.String())
+		}
+	}
+	return ips, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
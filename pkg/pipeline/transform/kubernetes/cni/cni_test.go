@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cni
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func node(annotations map[string]string) *v1.Node {
+	return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node", Annotations: annotations}}
+}
+
+func TestGetPlugins(t *testing.T) {
+	all, err := GetPlugins(nil)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	selected, err := GetPlugins([]string{"calico"})
+	require.NoError(t, err)
+	require.Equal(t, []Plugin{calico{}}, selected)
+
+	_, err = GetPlugins([]string{"flannel"})
+	require.Error(t, err)
+}
+
+func TestCalicoNodeIPs(t *testing.T) {
+	ips, err := calico{}.NodeIPs(node(map[string]string{
+		"projectcalico.org/IPv4Address":         "10.0.0.1/24",
+		"projectcalico.org/IPv4IPIPTunnelAddr":  "192.168.10.1",
+		"projectcalico.org/IPv6VXLANTunnelAddr": "fd00:10::1",
+	}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"192.168.10.1", "fd00:10::1"}, ips)
+
+	ips, err = calico{}.NodeIPs(node(nil))
+	require.NoError(t, err)
+	require.Empty(t, ips)
+
+	_, err = calico{}.NodeIPs(node(map[string]string{"projectcalico.org/IPv4VXLANTunnelAddr": "invalid"}))
+	require.Error(t, err)
+}
+
+func TestCiliumNodeIPs(t *testing.T) {
+	ips, err := cilium{}.NodeIPs(node(map[string]string{
+		"network.cilium.io/ipv4-cilium-host": "10.244.1.12",
+		"io.cilium.network.ipv4-cilium-host": "10.244.1.12",
+		"network.cilium.io/ipv6-cilium-host": "fd00::b",
+	}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.244.1.12", "fd00::b"}, ips)
+}
+
+func TestAddNodeIPs(t *testing.T) {
+	plugins, err := GetPlugins(nil)
+	require.NoError(t, err)
+	ips := AddNodeIPs([]string{"10.0.0.1"}, node(map[string]string{
+		ovnSubnetAnnotation:                    `{"default":"10.129.0.0/23"}`,
+		"projectcalico.org/IPv4IPIPTunnelAddr": "invalid",
+		"network.cilium.io/ipv4-cilium-host":   "10.244.1.12",
+	}), plugins)
+	// invalid annotations are ignored
+	require.ElementsMatch(t, []string{"10.0.0.1", "10.129.0.2", "10.244.1.12"}, ips)
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cni
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	networkStatusAnnotation = "k8s.v1.cni.cncf.io/network-status"
+)
+
+// networkStatus is an entry of the network-status annotation, as defined by the
+// Kubernetes Network Plumbing Working Group specification
+type networkStatus struct {
+	Name    string   `json:"name"`
+	IPs     []string `json:"ips"`
+	Default bool     `json:"default"`
+}
+
+// SecondaryNetworkIPs returns the IPs of the Pod on secondary networks (e.g. attached by Multus),
+// mapped to the network name (e.g. namespace/network-attachment-definition). The IPs of the default
+// network are ignored since they are already in the Pod status.
+func SecondaryNetworkIPs(pod *v1.Pod) (map[string]string, error) {
+	statusJSON, ok := pod.Annotations[networkStatusAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var statuses []networkStatus
+	if err := json.Unmarshal([]byte(statusJSON), &statuses); err != nil {
+		return nil, fmt.Errorf("cannot read annotation %s: %v", networkStatusAnnotation, err)
+	}
+	var ips map[string]string
+	for _, status := range statuses {
+		if status.Default {
+			continue
+		}
+		for _, ip := range status.IPs {
+			if ips == nil {
+				ips = map[string]string{}
+			}
+			ips[ip] = status.Name
+		}
+	}
+	return ips, nil
+}
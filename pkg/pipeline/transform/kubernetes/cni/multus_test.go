@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package cni
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSecondaryNetworkIPs(t *testing.T) {
+	pod := func(annotations map[string]string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+	ips, err := SecondaryNetworkIPs(pod(map[string]string{networkStatusAnnotation: `[
+		{"name": "ovn-kubernetes", "interface": "eth0", "ips": ["10.128.0.5"], "default": true},
+		{"name": "ns/macvlan", "interface": "net1", "ips": ["192.168.1.5", "fd00:1::5"], "mac": "0a:58:0a:80:00:05"},
+		{"name": "ns/sriov", "interface": "net2", "ips": ["192.168.2.5"]}
+	]`}))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"192.168.1.5": "ns/macvlan",
+		"fd00:1::5":   "ns/macvlan",
+		"192.168.2.5": "ns/sriov",
+	}, ips)
+
+	// annotation not found or without secondary networks
+	ips, err = SecondaryNetworkIPs(pod(nil))
+	require.NoError(t, err)
+	require.Nil(t, ips)
+	ips, err = SecondaryNetworkIPs(pod(map[string]string{networkStatusAnnotation: `[{"name": "ovn-kubernetes", "ips": ["10.128.0.5"], "default": true}]`}))
+	require.NoError(t, err)
+	require.Nil(t, ips)
+
+	_, err = SecondaryNetworkIPs(pod(map[string]string{networkStatusAnnotation: `{`}))
+	require.Error(t, err)
+}
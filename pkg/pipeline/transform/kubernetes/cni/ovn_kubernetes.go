@@ -22,7 +22,6 @@ import (
 	"fmt"
 	"net"
 
-	log "github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 )
 
@@ -30,41 +29,50 @@ const (
 	ovnSubnetAnnotation = "k8s.ovn.org/node-subnets"
 )
 
-func AddOvnIPs(ips []string, node *v1.Node) []string {
-	// Add IP that is used in OVN for some traffic on mp0 interface
-	// (no IP / error returned when not using ovn-k)
-	ip, err := findOvnMp0IP(node.Annotations)
-	if err != nil {
-		// Log the error as Info, do not block other ips indexing
-		log.Infof("failed to index OVN mp0 IP: %v", err)
-	} else if ip != "" {
-		return append(ips, ip)
-	}
-	return ips
+type ovnKubernetes struct{}
+
+// NodeIPs returns the IPs that are used in OVN for some traffic on mp0 interface
+// (no IP / error returned when not using ovn-k)
+func (ovnKubernetes) NodeIPs(node *v1.Node) ([]string, error) {
+	return findOvnMp0IPs(node.Annotations)
 }
 
-func findOvnMp0IP(annotations map[string]string) (string, error) {
+func findOvnMp0IPs(annotations map[string]string) ([]string, error) {
 	if subnetsJSON, ok := annotations[ovnSubnetAnnotation]; ok {
-		var subnets map[string]string
+		// single-stack clusters provide a subnet per network, dual-stack clusters provide a list of subnets
+		var subnets map[string]json.RawMessage
 		err := json.Unmarshal([]byte(subnetsJSON), &subnets)
 		if err != nil {
-			return "", fmt.Errorf("cannot read annotation %s: %v", ovnSubnetAnnotation, err)
+			return nil, fmt.Errorf("cannot read annotation %s: %v", ovnSubnetAnnotation, err)
+		}
+		raw, ok := subnets["default"]
+		if !ok {
+			return nil, fmt.Errorf("unexpected content for annotation %s: %s", ovnSubnetAnnotation, subnetsJSON)
+		}
+		var cidrs []string
+		if err := json.Unmarshal(raw, &cidrs); err != nil {
+			var cidr string
+			if err := json.Unmarshal(raw, &cidr); err != nil {
+				return nil, fmt.Errorf("unexpected content for annotation %s: %s", ovnSubnetAnnotation, subnetsJSON)
+			}
+			cidrs = []string{cidr}
 		}
-		if subnet, ok := subnets["default"]; ok {
-			// From subnet like 10.128.0.0/23, we want to index IP 10.128.0.2
-			ip0, _, err := net.ParseCIDR(subnet)
+		ips := make([]string, 0, len(cidrs))
+		for _, cidr := range cidrs {
+			// From subnet like 10.128.0.0/23, we want to index IP 10.128.0.2,
+			// and from fd01:0:0:1::/64, IP fd01:0:0:1::2
+			ip0, _, err := net.ParseCIDR(cidr)
 			if err != nil {
-				return "", err
+				return nil, err
 			}
-			ip4 := ip0.To4()
-			if ip4 == nil {
-				// TODO: what's the rule with ipv6?
-				return "", nil
+			if ip4 := ip0.To4(); ip4 != nil {
+				ip0 = ip4
 			}
-			return fmt.Sprintf("%d.%d.%d.2", ip4[0], ip4[1], ip4[2]), nil
+			ip0[len(ip0)-1] += 2
+			ips = append(ips, ip0.String())
 		}
-		return "", fmt.Errorf("unexpected content for annotation %s: %s", ovnSubnetAnnotation, subnetsJSON)
+		return ips, nil
 	}
 	// Annotation not present (expected if not using ovn-kubernetes) => just ignore, no error
-	return "", nil
+	return nil, nil
 }
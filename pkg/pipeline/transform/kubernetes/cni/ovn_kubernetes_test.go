@@ -23,32 +23,54 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestFindOvnMp0IP(t *testing.T) {
+func TestFindOvnMp0IPs(t *testing.T) {
 	// Annotation not found => no error, no ip
-	ip, err := findOvnMp0IP(map[string]string{})
+	ips, err := findOvnMp0IPs(map[string]string{})
 	require.NoError(t, err)
-	require.Empty(t, ip)
+	require.Empty(t, ips)
 
 	// Annotation malformed => error, no ip
-	ip, err = findOvnMp0IP(map[string]string{
+	ips, err = findOvnMp0IPs(map[string]string{
 		ovnSubnetAnnotation: "whatever",
 	})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "cannot read annotation")
-	require.Empty(t, ip)
+	require.Empty(t, ips)
+
+	// Unexpected content => error, no ip
+	ips, err = findOvnMp0IPs(map[string]string{
+		ovnSubnetAnnotation: `{"default":{"a":"b"}}`,
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unexpected content")
+	require.Empty(t, ips)
 
 	// IP malformed => error, no ip
-	ip, err = findOvnMp0IP(map[string]string{
+	ips, err = findOvnMp0IPs(map[string]string{
 		ovnSubnetAnnotation: `{"default":"10.129/23"}`,
 	})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "invalid CIDR address")
-	require.Empty(t, ip)
+	require.Empty(t, ips)
 
 	// Valid annotation => no error, ip
-	ip, err = findOvnMp0IP(map[string]string{
+	ips, err = findOvnMp0IPs(map[string]string{
 		ovnSubnetAnnotation: `{"default":"10.129.0.0/23"}`,
 	})
 	require.NoError(t, err)
-	require.Equal(t, "10.129.0.2", ip)
+	require.Equal(t, []string{"10.129.0.2"}, ips)
+
+	// Dual-stack annotation => IPv4 and IPv6 ips
+	ips, err = findOvnMp0IPs(map[string]string{
+		ovnSubnetAnnotation: `{"default":["10.129.0.0/23","fd01:0:0:1::/64"]}`,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.129.0.2", "fd01:0:0:1::2"}, ips)
+
+	// IPv6 single-stack annotation
+	ips, err = findOvnMp0IPs(map[string]string{
+		ovnSubnetAnnotation: `{"default":"fd01:0:0:2::/64"}`,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"fd01:0:0:2::2"}, ips)
 }
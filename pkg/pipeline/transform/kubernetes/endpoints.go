@@ -0,0 +1,205 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+const indexService = "byService"
+
+// ServiceEndpoints relates an IP and port to Services. ServiceName is the Service backed by a Pod endpoint,
+// while Endpoints are the candidate endpoints, as ip:port, of a Service IP.
+type ServiceEndpoints struct {
+	ServiceName string
+	Endpoints   []string
+}
+
+// servicePort is a port of a Service or of an EndpointSlice. In EndpointSlices, the port is the
+// target port, and the name is the one of the Service port it implements.
+type servicePort struct {
+	name     string
+	port     int
+	protocol string
+}
+
+func (p *servicePort) matches(port int, protocol string) bool {
+	return p.port == port && (protocol == "" || p.protocol == protocol)
+}
+
+// endpointSlice contains the precollected metadata of an EndpointSlice. Only the addresses of the
+// ready endpoints are kept.
+type endpointSlice struct {
+	// Informers need that internal object is an ObjectMeta instance
+	metav1.ObjectMeta
+	// service is the namespace/name key of the Service the EndpointSlice belongs to
+	service string
+	ports   []servicePort
+	ips     []string
+}
+
+var endpointSliceIndexers = map[string]cache.IndexFunc{
+	IndexIP: func(obj interface{}) ([]string, error) {
+		return obj.(*endpointSlice).ips, nil
+	},
+	indexService: func(obj interface{}) ([]string, error) {
+		return []string{obj.(*endpointSlice).service}, nil
+	},
+}
+
+// GetServiceEndpoints returns, for a Service IP, the candidate endpoints of the Service port, or, for a
+// Pod IP, the Service backed by the Pod port. When several Services are backed by the same Pod port, the
+// first one by name is returned. The protocol (TCP, UDP or SCTP) is ignored when empty, as well as the
+// port for Service IPs when it's 0, returning then the endpoints of all the Service ports.
+func (k *KubeData) GetServiceEndpoints(ip string, port int, protocol string) (*ServiceEndpoints, error) {
+	if k.endpointSlices == nil {
+		return nil, errors.New("EndpointSlices aren't watched")
+	}
+	if svc, ok := infoForIP(k.services.GetIndexer(), ip); ok {
+		return &ServiceEndpoints{ServiceName: svc.Name, Endpoints: k.serviceEndpoints(svc, port, protocol)}, nil
+	}
+	if port == 0 {
+		return nil, fmt.Errorf("no port to find the Service of IP %s", ip)
+	}
+	objs, err := k.endpointSlices.GetIndexer().ByIndex(IndexIP, ip)
+	if err != nil {
+		return nil, fmt.Errorf("can't access EndpointSlices index: %w", err)
+	}
+	var services []string
+	for _, obj := range objs {
+		slice := obj.(*endpointSlice)
+		for i := range slice.ports {
+			if slice.ports[i].matches(port, protocol) {
+				services = append(services, slice.service)
+				break
+			}
+		}
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no Service found for IP %s and port %d", ip, port)
+	}
+	sort.Strings(services)
+	_, name, err := cache.SplitMetaNamespaceKey(services[0])
+	if err != nil {
+		return nil, err
+	}
+	return &ServiceEndpoints{ServiceName: name}, nil
+}
+
+// serviceEndpoints returns the sorted ip:port addresses of the Service endpoints implementing the port
+func (k *KubeData) serviceEndpoints(svc *Info, port int, protocol string) []string {
+	portName := ""
+	if port != 0 {
+		found := false
+		for i := range svc.ports {
+			if svc.ports[i].matches(port, protocol) {
+				portName, found = svc.ports[i].name, true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	key := svc.Namespace + "/" + svc.Name
+	objs, err := k.endpointSlices.GetIndexer().ByIndex(indexService, key)
+	if err != nil {
+		log.WithError(err).WithField("key", key).Debug("can't get EndpointSlices from informer. Ignoring")
+		return nil
+	}
+	unique := map[string]struct{}{}
+	for _, obj := range objs {
+		slice := obj.(*endpointSlice)
+		for i := range slice.ports {
+			if port != 0 && (slice.ports[i].name != portName || (protocol != "" && slice.ports[i].protocol != protocol)) {
+				continue
+			}
+			for _, ip := range slice.ips {
+				unique[net.JoinHostPort(ip, strconv.Itoa(slice.ports[i].port))] = struct{}{}
+			}
+		}
+	}
+	endpoints := make([]string, 0, len(unique))
+	for endpoint := range unique {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	return endpoints
+}
+
+func (k *KubeData) initEndpointSliceInformer(informerFactory informers.SharedInformerFactory) error {
+	slices := informerFactory.Discovery().V1().EndpointSlices().Informer()
+	// Transform any *discoveryv1.EndpointSlice instance into a *endpointSlice instance to save space
+	// in the informer's cache
+	if err := slices.SetTransform(func(i interface{}) (interface{}, error) {
+		slice, ok := i.(*discoveryv1.EndpointSlice)
+		if !ok {
+			return nil, fmt.Errorf("was expecting an EndpointSlice. Got: %T", i)
+		}
+		return transformEndpointSlice(slice), nil
+	}); err != nil {
+		return fmt.Errorf("can't set EndpointSlices transform: %w", err)
+	}
+	if err := slices.AddIndexers(endpointSliceIndexers); err != nil {
+		return fmt.Errorf("can't add indexers to EndpointSlices informer: %w", err)
+	}
+	k.endpointSlices = slices
+	return nil
+}
+
+func transformEndpointSlice(slice *discoveryv1.EndpointSlice) *endpointSlice {
+	es := &endpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      slice.Name,
+			Namespace: slice.Namespace,
+		},
+		service: slice.Namespace + "/" + slice.Labels[discoveryv1.LabelServiceName],
+	}
+	for _, p := range slice.Ports {
+		// a nil port means all the ports, which is only used by Services without selector nor ports
+		if p.Port == nil {
+			continue
+		}
+		sp := servicePort{port: int(*p.Port)}
+		if p.Name != nil {
+			sp.name = *p.Name
+		}
+		if p.Protocol != nil {
+			sp.protocol = string(*p.Protocol)
+		}
+		es.ports = append(es.ports, sp)
+	}
+	for i := range slice.Endpoints {
+		// the readiness is unknown when nil, and then should be interpreted as ready
+		if ready := slice.Endpoints[i].Conditions.Ready; ready != nil && !*ready {
+			continue
+		}
+		es.ips = append(es.ips, slice.Endpoints[i].Addresses...)
+	}
+	return es
+}
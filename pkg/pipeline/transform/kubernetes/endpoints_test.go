@@ -0,0 +1,117 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func endpointPort(name string, port int32) discoveryv1.EndpointPort {
+	protocol := v1.ProtocolTCP
+	return discoveryv1.EndpointPort{Name: &name, Port: &port, Protocol: &protocol}
+}
+
+func TestTransformEndpointSlice(t *testing.T) {
+	notReady := false
+	slice := transformEndpointSlice(&discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-abcde",
+			Namespace: "ns",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "web"},
+		},
+		Ports: []discoveryv1.EndpointPort{endpointPort("http", 8080), {}},
+		Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"10.128.0.5"}},
+			{Addresses: []string{"10.128.0.6"}, Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+		},
+	})
+	require.Equal(t, "ns/web", slice.service)
+	require.Equal(t, []servicePort{{name: "http", port: 8080, protocol: "TCP"}}, slice.ports)
+	// not ready endpoints are ignored
+	require.Equal(t, []string{"10.128.0.5"}, slice.ips)
+}
+
+func TestGetServiceEndpoints(t *testing.T) {
+	newInformer := func(indexers cache.Indexers, objs ...interface{}) cache.SharedIndexInformer {
+		idx := cache.NewIndexer(cache.MetaNamespaceKeyFunc, indexers)
+		for _, obj := range objs {
+			require.NoError(t, idx.Add(obj))
+		}
+		im := InformerMock{}
+		im.On("GetIndexer").Return(idx)
+		return &im
+	}
+	slice := func(name, service string, ports []servicePort, ips ...string) *endpointSlice {
+		return &endpointSlice{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			service:    "ns/" + service,
+			ports:      ports,
+			ips:        ips,
+		}
+	}
+	webPorts := []servicePort{{name: "http", port: 8080, protocol: "TCP"}, {name: "metrics", port: 9090, protocol: "TCP"}}
+	kubeData := KubeData{
+		services: newInformer(commonIndexers, &Info{
+			Type:       typeService,
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns"},
+			ips:        []string{"172.30.0.10"},
+			ports:      []servicePort{{name: "http", port: 80, protocol: "TCP"}, {name: "metrics", port: 9090, protocol: "TCP"}},
+		}),
+		endpointSlices: newInformer(endpointSliceIndexers,
+			slice("web-1", "web", webPorts, "10.128.0.5", "10.128.0.6"),
+			slice("web-2", "web", webPorts, "fd00::5"),
+			slice("web-headless-1", "web-headless", webPorts[:1], "10.128.0.5"),
+			slice("other-1", "other", []servicePort{{name: "dns", port: 53, protocol: "UDP"}}, "10.128.0.5"),
+		),
+	}
+
+	// Service IP: candidate endpoints of the Service port
+	endpoints, err := kubeData.GetServiceEndpoints("172.30.0.10", 80, "TCP")
+	require.NoError(t, err)
+	require.Equal(t, "web", endpoints.ServiceName)
+	require.Equal(t, []string{"10.128.0.5:8080", "10.128.0.6:8080", "[fd00::5]:8080"}, endpoints.Endpoints)
+	endpoints, err = kubeData.GetServiceEndpoints("172.30.0.10", 0, "")
+	require.NoError(t, err)
+	require.Len(t, endpoints.Endpoints, 6)
+	endpoints, err = kubeData.GetServiceEndpoints("172.30.0.10", 443, "TCP")
+	require.NoError(t, err)
+	require.Empty(t, endpoints.Endpoints)
+
+	// Pod IP: Services backed by the Pod port, first by name
+	endpoints, err = kubeData.GetServiceEndpoints("10.128.0.5", 8080, "TCP")
+	require.NoError(t, err)
+	require.Equal(t, "web", endpoints.ServiceName)
+	require.Nil(t, endpoints.Endpoints)
+	endpoints, err = kubeData.GetServiceEndpoints("10.128.0.5", 53, "")
+	require.NoError(t, err)
+	require.Equal(t, "other", endpoints.ServiceName)
+	_, err = kubeData.GetServiceEndpoints("10.128.0.5", 53, "TCP")
+	require.Error(t, err)
+	_, err = kubeData.GetServiceEndpoints("10.128.0.5", 0, "TCP")
+	require.Error(t, err)
+
+	// not watched
+	_, err = (&KubeData{}).GetServiceEndpoints("172.30.0.10", 80, "TCP")
+	require.Error(t, err)
+}
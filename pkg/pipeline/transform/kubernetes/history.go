@@ -0,0 +1,95 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package kubernetes
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// ipHistory keeps the past IP assignments of deleted objects, so that delayed flows (e.g. because of Kafka
+// lag or replays) are attributed to the object owning the IP at the time of the flow, even if the IP was
+// reassigned since. It is bounded both in number of assignments and in age.
+type ipHistory struct {
+	maxEntries int
+	maxAge     time.Duration
+	mutex      sync.RWMutex
+	// byIP holds the assignments of each IP, from the oldest to the newest
+	byIP map[string][]*assignment
+	// assignments holds all the *assignment, ordered by end time, for eviction
+	assignments *list.List
+}
+
+// assignment is the validity interval of an IP for an object
+type assignment struct {
+	ip       string
+	info     *Info
+	from, to time.Time
+}
+
+func newIPHistory(maxEntries int, maxAge time.Duration) *ipHistory {
+	return &ipHistory{
+		maxEntries:  maxEntries,
+		maxAge:      maxAge,
+		byIP:        map[string][]*assignment{},
+		assignments: list.New(),
+	}
+}
+
+// add records the IPs of an object valid from its creation until the given time
+func (h *ipHistory) add(info *Info, to time.Time) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for _, ip := range info.ips {
+		a := &assignment{ip: ip, info: info, from: info.CreationTimestamp.Time, to: to}
+		h.byIP[ip] = append(h.byIP[ip], a)
+		h.assignments.PushBack(a)
+	}
+	h.evict(to)
+}
+
+// evict removes the oldest assignments beyond the maximum number of entries or age
+func (h *ipHistory) evict(now time.Time) {
+	for oldest := h.assignments.Front(); oldest != nil; oldest = h.assignments.Front() {
+		a := oldest.Value.(*assignment)
+		if h.assignments.Len() <= h.maxEntries && now.Sub(a.to) <= h.maxAge {
+			return
+		}
+		h.assignments.Remove(oldest)
+		// being the oldest assignment, it's also the first one of its IP
+		if ipAssignments := h.byIP[a.ip]; len(ipAssignments) > 1 {
+			h.byIP[a.ip] = ipAssignments[1:]
+		} else {
+			delete(h.byIP, a.ip)
+		}
+	}
+}
+
+// lookup returns the object owning the IP at the given time, if any
+func (h *ipHistory) lookup(ip string, at time.Time) (*Info, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	ipAssignments := h.byIP[ip]
+	for i := len(ipAssignments) - 1; i >= 0; i-- {
+		if a := ipAssignments[i]; !at.Before(a.from) && !at.After(a.to) {
+			return a.info, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+var historyStart = time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func historyPod(name string, created time.Time, ips ...string) *Info {
+	return &Info{
+		Type: typePod,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "ns",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		ips: ips,
+	}
+}
+
+func TestIPHistory_Lookup(t *testing.T) {
+	h := newIPHistory(10, time.Hour)
+	h.add(historyPod("first", historyStart, "10.0.0.1"), historyStart.Add(time.Minute))
+	h.add(historyPod("second", historyStart.Add(2*time.Minute), "10.0.0.1", "fd00::1"), historyStart.Add(3*time.Minute))
+
+	info, ok := h.lookup("10.0.0.1", historyStart.Add(30*time.Second))
+	require.True(t, ok)
+	require.Equal(t, "first", info.Name)
+	info, ok = h.lookup("10.0.0.1", historyStart.Add(3*time.Minute))
+	require.True(t, ok)
+	require.Equal(t, "second", info.Name)
+	info, ok = h.lookup("fd00::1", historyStart.Add(150*time.Second))
+	require.True(t, ok)
+	require.Equal(t, "second", info.Name)
+
+	// unassigned between the two Pods, before the first one and after the second one
+	_, ok = h.lookup("10.0.0.1", historyStart.Add(90*time.Second))
+	require.False(t, ok)
+	_, ok = h.lookup("10.0.0.1", historyStart.Add(-time.Second))
+	require.False(t, ok)
+	_, ok = h.lookup("10.0.0.1", historyStart.Add(4*time.Minute))
+	require.False(t, ok)
+	_, ok = h.lookup("10.0.0.2", historyStart)
+	require.False(t, ok)
+}
+
+func TestIPHistory_Eviction(t *testing.T) {
+	h := newIPHistory(2, time.Hour)
+	h.add(historyPod("first", historyStart, "10.0.0.1"), historyStart.Add(time.Minute))
+	h.add(historyPod("second", historyStart, "10.0.0.2"), historyStart.Add(2*time.Minute))
+	h.add(historyPod("third", historyStart, "10.0.0.1"), historyStart.Add(3*time.Minute))
+
+	// the first assignment is evicted because of the maximum number of entries
+	require.Equal(t, 2, h.assignments.Len())
+	info, ok := h.lookup("10.0.0.1", historyStart.Add(30*time.Second))
+	require.True(t, ok)
+	require.Equal(t, "third", info.Name)
+	require.Len(t, h.byIP["10.0.0.1"], 1)
+
+	// the second assignment is evicted because of its age
+	h.add(historyPod("fourth", historyStart, "10.0.0.3"), historyStart.Add(2*time.Minute+time.Hour+time.Second))
+	require.Equal(t, 2, h.assignments.Len())
+	_, ok = h.lookup("10.0.0.2", historyStart.Add(time.Minute))
+	require.False(t, ok)
+	require.NotContains(t, h.byIP, "10.0.0.2")
+}
+
+func TestGetInfoFromHistory(t *testing.T) {
+	newInformer := func(objs ...interface{}) cache.SharedIndexInformer {
+		idx := cache.NewIndexer(cache.MetaNamespaceKeyFunc, commonIndexers)
+		for _, obj := range objs {
+			require.NoError(t, idx.Add(obj))
+		}
+		im := InformerMock{}
+		im.On("GetIndexer").Return(idx)
+		return &im
+	}
+	node := &Info{Type: typeNode, ObjectMeta: metav1.ObjectMeta{Name: "node"}, ips: []string{"10.0.0.1"}}
+	deleted := historyPod("deleted", historyStart, "10.128.0.5")
+	deleted.HostIP = "10.0.0.1"
+	deleted.OwnerReferences = []metav1.OwnerReference{{APIVersion: "batch/v1", Kind: "Job", Name: "backup"}}
+	kubeData := KubeData{
+		ownerDepth: 1,
+		history:    newIPHistory(10, time.Hour),
+		pods:       newInformer(historyPod("current", historyStart.Add(10*time.Minute), "10.128.0.5")),
+		nodes:      newInformer(node),
+		services:   newInformer(),
+	}
+	// the deletion tombstone is unwrapped
+	kubeData.onPodDeleted(cache.DeletedFinalStateUnknown{Key: "ns/deleted", Obj: deleted})
+
+	// the current Pod was created after the flow: the deleted Pod owned the IP
+	info, err := kubeData.GetInfo("10.128.0.5", historyStart.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, "deleted", info.Name)
+	require.True(t, info.FromHistory)
+	require.Equal(t, "node", info.HostName)
+	require.Equal(t, Owner{Type: "Job", Name: "backup"}, info.Owner)
+
+	info, err = kubeData.GetInfo("10.128.0.5", historyStart.Add(11*time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, "current", info.Name)
+	require.False(t, info.FromHistory)
+
+	// with no time, or no matching assignment, the current Pod is returned
+	info, err = kubeData.GetInfo("10.128.0.5", time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, "current", info.Name)
+	info, err = kubeData.GetInfo("10.128.0.5", historyStart.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, "current", info.Name)
+
+	// IPs of deleted Pods that weren't reassigned are also found
+	kubeData.onPodDeleted(historyPod("gone", historyStart, "10.128.0.6"))
+	info, err = kubeData.GetInfo("10.128.0.6", historyStart.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, "gone", info.Name)
+	require.True(t, info.FromHistory)
+	_, err = kubeData.GetInfo("10.128.0.6", time.Time{})
+	require.Error(t, err)
+}
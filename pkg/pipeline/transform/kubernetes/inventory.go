@@ -0,0 +1,291 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/utils"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Inventory is a snapshot of the Kubernetes objects used for enrichment. It allows enriching flows
+// without a live cluster, e.g. when replaying flows or for VM fleets.
+type Inventory struct {
+	Pods       []InventoryObject `yaml:"pods,omitempty" json:"pods,omitempty"`
+	Nodes      []InventoryObject `yaml:"nodes,omitempty" json:"nodes,omitempty"`
+	Services   []InventoryObject `yaml:"services,omitempty" json:"services,omitempty"`
+	Namespaces []InventoryObject `yaml:"namespaces,omitempty" json:"namespaces,omitempty"`
+}
+
+// InventoryObject holds the metadata of a Pod, Node, Service or Namespace. Not all the fields apply to all
+// the types: for example, owners, host IPs, named ports and networks, which map the IPs on secondary networks
+// to their network name, are only set for Pods. When no owner is provided, the object is its own owner.
+type InventoryObject struct {
+	Name           string            `yaml:"name" json:"name"`
+	Namespace      string            `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	IPs            []string          `yaml:"ips,omitempty" json:"ips,omitempty"`
+	HostIP         string            `yaml:"hostIP,omitempty" json:"hostIP,omitempty"`
+	Labels         map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations    map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+	Networks       map[string]string `yaml:"networks,omitempty" json:"networks,omitempty"`
+	Ports          []InventoryPort   `yaml:"ports,omitempty" json:"ports,omitempty"`
+	Owner          *Owner            `yaml:"owner,omitempty" json:"owner,omitempty"`
+	ImmediateOwner *Owner            `yaml:"immediateOwner,omitempty" json:"immediateOwner,omitempty"`
+}
+
+// InventoryPort is a named container port of a Pod, used to resolve the named ports of NetworkPolicies.
+// The protocol defaults to TCP.
+type InventoryPort struct {
+	Name     string `yaml:"name" json:"name"`
+	Port     int    `yaml:"port" json:"port"`
+	Protocol string `yaml:"protocol,omitempty" json:"protocol,omitempty"`
+}
+
+// InventoryData is a kubeDataInterface implementation that reads the Kubernetes objects
+// from an inventory file, which is reloaded when modified
+type InventoryData struct {
+	reloadPeriod time.Duration
+	cfg          *api.NetworkTransformKubernetes
+	// mutex protects infos, which is replaced on reloads
+	mutex sync.RWMutex
+	infos map[string]*Info
+}
+
+func NewInventoryData(reloadPeriod time.Duration) *InventoryData {
+	return &InventoryData{reloadPeriod: reloadPeriod}
+}
+
+// GetInfo returns the object owning the IP in the inventory. The time is ignored, since the
+// inventory is a snapshot.
+func (d *InventoryData) GetInfo(ip string, _ time.Time) (*Info, error) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	if info, ok := d.infos[ip]; ok {
+		return withNetwork(info, ip), nil
+	}
+	return nil, fmt.Errorf("inventory doesn't contain IP %s", ip)
+}
+
+// GetServiceEndpoints always fails, since inventories don't contain EndpointSlices
+func (d *InventoryData) GetServiceEndpoints(ip string, _ int, _ string) (*ServiceEndpoints, error) {
+	return nil, fmt.Errorf("inventory doesn't contain endpoints for IP %s", ip)
+}
+
+// InitFromConfig loads the inventory file from the configuration. The kubeconfig path is ignored.
+func (d *InventoryData) InitFromConfig(_ string, cfg *api.NetworkTransformKubernetes) error {
+	path := cfg.GetInventoryFile()
+	if path == "" {
+		return errors.New("no Kubernetes inventory file configured")
+	}
+	d.cfg = cfg
+	if err := d.load(path); err != nil {
+		return err
+	}
+	utils.WatchFiles([]string{path}, d.reloadPeriod, func() {
+		if err := d.load(path); err != nil {
+			log.WithError(err).Error("can't reload Kubernetes inventory. Keeping previous one")
+			return
+		}
+		log.WithField("path", path).Info("Kubernetes inventory reloaded")
+	})
+	return nil
+}
+
+func (d *InventoryData) load(path string) error {
+	inventory, err := LoadInventory(path)
+	if err != nil {
+		return err
+	}
+	infos := inventory.index(d.cfg)
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.infos = infos
+	return nil
+}
+
+// LoadInventory reads an inventory from a YAML or JSON file
+func LoadInventory(path string) (*Inventory, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading Kubernetes inventory %q: %w", path, err)
+	}
+	var inventory Inventory
+	if err := yaml.UnmarshalStrict(content, &inventory); err != nil {
+		return nil, fmt.Errorf("parsing Kubernetes inventory %q: %w", path, err)
+	}
+	return &inventory, nil
+}
+
+// index builds the Info of each IP. As for live clusters, Pods take precedence over Nodes, which take
+// precedence over Services, and the namespace labels, Pod annotations and Node labels are filtered
+// according to the allow-lists from the configuration.
+func (inv *Inventory) index(cfg *api.NetworkTransformKubernetes) map[string]*Info {
+	var namespaceLabels, podAnnotations, nodeLabels []string
+	if cfg != nil {
+		namespaceLabels, podAnnotations, nodeLabels = cfg.NamespaceLabels, cfg.PodAnnotations, cfg.NodeLabels
+	}
+	nsLabels := map[string]map[string]string{}
+	for i := range inv.Namespaces {
+		nsLabels[inv.Namespaces[i].Name] = filterKeys(inv.Namespaces[i].Labels, namespaceLabels)
+	}
+	nodeByIP := map[string]*InventoryObject{}
+	for i := range inv.Nodes {
+		for _, ip := range inv.Nodes[i].IPs {
+			nodeByIP[ip] = &inv.Nodes[i]
+		}
+	}
+
+	infos := map[string]*Info{}
+	add := func(obj *InventoryObject, info *Info) {
+		info.ObjectMeta.Name = obj.Name
+		info.ObjectMeta.Namespace = obj.Namespace
+		info.ObjectMeta.Labels = obj.Labels
+		info.NamespaceLabels = nsLabels[obj.Namespace]
+		info.Owner = Owner{Name: obj.Name, Type: info.Type}
+		if obj.Owner != nil {
+			info.Owner = *obj.Owner
+		}
+		info.ImmediateOwner = info.Owner
+		if obj.ImmediateOwner != nil {
+			info.ImmediateOwner = *obj.ImmediateOwner
+		}
+		for _, ip := range info.ips {
+			infos[ip] = info
+		}
+	}
+	for i := range inv.Services {
+		add(&inv.Services[i], &Info{Type: typeService, ips: inv.Services[i].IPs})
+	}
+	for i := range inv.Nodes {
+		node := &inv.Nodes[i]
+		info := &Info{Type: typeNode, ips: node.IPs, HostName: node.Name, NodeLabels: filterKeys(node.Labels, nodeLabels)}
+		if len(node.IPs) > 0 {
+			info.HostIP = node.IPs[0]
+		}
+		add(node, info)
+	}
+	for i := range inv.Pods {
+		pod := &inv.Pods[i]
+		info := &Info{Type: typePod, HostIP: pod.HostIP, networks: pod.Networks}
+		for _, p := range pod.Ports {
+			protocol := p.Protocol
+			if protocol == "" {
+				protocol = string(v1.ProtocolTCP)
+			}
+			info.ports = append(info.ports, servicePort{name: p.Name, port: p.Port, protocol: protocol})
+		}
+		info.ObjectMeta.Annotations = filterKeys(pod.Annotations, podAnnotations)
+		// ignoring host-networked Pod IPs
+		for _, ip := range pod.IPs {
+			if ip != pod.HostIP {
+				info.ips = append(info.ips, ip)
+			}
+		}
+		if node, ok := nodeByIP[pod.HostIP]; ok {
+			info.HostName = node.Name
+			info.NodeLabels = filterKeys(node.Labels, nodeLabels)
+		}
+		add(pod, info)
+	}
+	return infos
+}
+
+// DumpInventory takes a snapshot of the cluster, to be used as an inventory file. Owners are resolved
+// up to the configured depth, and only the allow-listed namespace labels and Pod annotations are kept.
+func DumpInventory(client kubernetes.Interface, cfg *api.NetworkTransformKubernetes) (*Inventory, error) {
+	k := &KubeData{stopChan: make(chan struct{})}
+	defer close(k.stopChan)
+	if err := k.setConfig(cfg); err != nil {
+		return nil, err
+	}
+	if err := k.initInformers(client); err != nil {
+		return nil, err
+	}
+
+	inventory := Inventory{}
+	for _, obj := range k.pods.GetIndexer().List() {
+		info := obj.(*Info)
+		immediate, top := k.getOwners(info)
+		var ports []InventoryPort
+		for _, p := range info.ports {
+			ports = append(ports, InventoryPort{Name: p.name, Port: p.port, Protocol: p.protocol})
+		}
+		inventory.Pods = append(inventory.Pods, InventoryObject{
+			Name:           info.Name,
+			Namespace:      info.Namespace,
+			IPs:            info.ips,
+			HostIP:         info.HostIP,
+			Labels:         info.Labels,
+			Annotations:    info.Annotations,
+			Networks:       info.networks,
+			Ports:          ports,
+			Owner:          &top,
+			ImmediateOwner: &immediate,
+		})
+	}
+	for _, obj := range k.nodes.GetIndexer().List() {
+		info := obj.(*Info)
+		inventory.Nodes = append(inventory.Nodes, InventoryObject{
+			Name:   info.Name,
+			IPs:    info.ips,
+			Labels: info.Labels,
+		})
+	}
+	for _, obj := range k.services.GetIndexer().List() {
+		info := obj.(*Info)
+		inventory.Services = append(inventory.Services, InventoryObject{
+			Name:      info.Name,
+			Namespace: info.Namespace,
+			IPs:       info.ips,
+			Labels:    info.Labels,
+		})
+	}
+	if k.namespaces != nil {
+		for _, obj := range k.namespaces.GetIndexer().List() {
+			ns := obj.(*metav1.ObjectMeta)
+			inventory.Namespaces = append(inventory.Namespaces, InventoryObject{
+				Name:   ns.Name,
+				Labels: ns.Labels,
+			})
+		}
+	}
+	for _, objs := range [][]InventoryObject{inventory.Pods, inventory.Nodes, inventory.Services, inventory.Namespaces} {
+		sortObjects(objs)
+	}
+	return &inventory, nil
+}
+
+func sortObjects(objs []InventoryObject) {
+	sort.Slice(objs, func(i, j int) bool {
+		if objs[i].Namespace != objs[j].Namespace {
+			return objs[i].Namespace < objs[j].Namespace
+		}
+		return objs[i].Name < objs[j].Name
+	})
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package kubernetes
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestInventoryData(t *testing.T) {
+	data := NewInventoryData(time.Minute)
+	require.NoError(t, data.InitFromConfig("", &api.NetworkTransformKubernetes{
+		InventoryFile:   filepath.Join("testdata", "inventory.yaml"),
+		NamespaceLabels: []string{"cost-center"},
+		PodAnnotations:  []string{"example.com/team"},
+		NodeLabels:      []string{"topology.kubernetes.io/zone"},
+	}))
+
+	info, err := data.GetInfo("10.128.0.5", time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, &Info{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web-5d8f7-abcde",
+			Namespace:   "shop",
+			Labels:      map[string]string{"app": "web"},
+			Annotations: map[string]string{"example.com/team": "frontend"},
+		},
+		Type:            typePod,
+		Owner:           Owner{Type: "Deployment", Name: "web"},
+		ImmediateOwner:  Owner{Type: "ReplicaSet", Name: "web-5d8f7"},
+		HostName:        "node-1",
+		HostIP:          "10.0.0.1",
+		NamespaceLabels: map[string]string{"cost-center": "1234"},
+		NodeLabels:      map[string]string{"topology.kubernetes.io/zone": "us-east-1a"},
+		networks:        map[string]string{"192.168.1.5": "shop/macvlan"},
+		ips:             []string{"10.128.0.5", "192.168.1.5"},
+		ports:           []servicePort{{name: "http", port: 8080, protocol: "TCP"}},
+	}, info)
+
+	// secondary network IP
+	info, err = data.GetInfo("192.168.1.5", time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, "web-5d8f7-abcde", info.Name)
+	require.Equal(t, "shop/macvlan", info.Network)
+
+	// host-networked Pod IPs resolve to the Node
+	info, err = data.GetInfo("10.0.0.1", time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, typeNode, info.Type)
+	require.Equal(t, "node-1", info.Name)
+	require.Equal(t, "node-1", info.HostName)
+	require.Equal(t, Owner{Type: typeNode, Name: "node-1"}, info.Owner)
+
+	info, err = data.GetInfo("172.30.0.10", time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, typeService, info.Type)
+	require.Equal(t, Owner{Type: typeService, Name: "web"}, info.ImmediateOwner)
+
+	_, err = data.GetInfo("1.2.3.4", time.Time{})
+	require.Error(t, err)
+}
+
+func TestInventoryData_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.json")
+	write := func(inv Inventory) {
+		content, err := json.Marshal(inv)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, content, 0600))
+	}
+	write(Inventory{Pods: []InventoryObject{{Name: "pod-1", Namespace: "ns", IPs: []string{"10.128.0.1"}}}})
+	data := NewInventoryData(10 * time.Millisecond)
+	require.NoError(t, data.InitFromConfig("", &api.NetworkTransformKubernetes{InventoryFile: path}))
+	info, err := data.GetInfo("10.128.0.1", time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, "pod-1", info.Name)
+
+	// invalid content: previous inventory is kept
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, os.WriteFile(path, []byte("{invalid"), 0600))
+	time.Sleep(50 * time.Millisecond)
+	_, err = data.GetInfo("10.128.0.1", time.Time{})
+	require.NoError(t, err)
+
+	write(Inventory{Pods: []InventoryObject{{Name: "pod-2", Namespace: "ns", IPs: []string{"10.128.0.2", "10.128.0.20"}}}})
+	require.Eventually(t, func() bool {
+		info, err := data.GetInfo("10.128.0.2", time.Time{})
+		return err == nil && info.Name == "pod-2"
+	}, time.Second, 10*time.Millisecond)
+	_, err = data.GetInfo("10.128.0.1", time.Time{})
+	require.Error(t, err)
+}
+
+func TestInventoryData_Errors(t *testing.T) {
+	require.Error(t, NewInventoryData(time.Minute).InitFromConfig("", nil))
+	require.Error(t, NewInventoryData(time.Minute).InitFromConfig("", &api.NetworkTransformKubernetes{
+		InventoryFile: filepath.Join("testdata", "not-found.yaml"),
+	}))
+	path := filepath.Join(t.TempDir(), "inventory.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("pods:\n  - name: a\n    unknownField: b\n"), 0600))
+	require.Error(t, NewInventoryData(time.Minute).InitFromConfig("", &api.NetworkTransformKubernetes{InventoryFile: path}))
+}
+
+func TestDumpInventory(t *testing.T) {
+	controller := true
+	client := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"cost-center": "1234", "other": "x"}}},
+		&v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "node-1",
+				Labels:      map[string]string{"topology.kubernetes.io/zone": "us-east-1a"},
+				Annotations: map[string]string{"projectcalico.org/IPv4IPIPTunnelAddr": "192.168.10.1"},
+			},
+			Status: v1.NodeStatus{Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.1"}}},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "web-5d8f7-abcde", Namespace: "shop",
+				Labels: map[string]string{"app": "web"},
+				Annotations: map[string]string{
+					"example.com/team":  "frontend",
+					"example.com/other": "ignored",
+					"k8s.v1.cni.cncf.io/network-status": `[{"name":"ovn-kubernetes","ips":["10.128.0.5"],"default":true},` +
+						`{"name":"shop/macvlan","ips":["192.168.1.5"]}]`,
+				},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-5d8f7", Controller: &controller,
+				}},
+			},
+			Spec: v1.PodSpec{Containers: []v1.Container{{
+				Name:  "web",
+				Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8080, Protocol: v1.ProtocolTCP}, {ContainerPort: 9090}},
+			}}},
+			Status: v1.PodStatus{HostIP: "10.0.0.1", PodIPs: []v1.PodIP{{IP: "10.128.0.5"}}},
+		},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "web-5d8f7", Namespace: "shop",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Controller: &controller,
+			}},
+		}},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Spec:       v1.ServiceSpec{ClusterIP: "172.30.0.10", ClusterIPs: []string{"172.30.0.10"}},
+		},
+	)
+	inventory, err := DumpInventory(client, &api.NetworkTransformKubernetes{
+		NamespaceLabels: []string{"cost-center"},
+		PodAnnotations:  []string{"example.com/team"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &Inventory{
+		Pods: []InventoryObject{{
+			Name:           "web-5d8f7-abcde",
+			Namespace:      "shop",
+			IPs:            []string{"10.128.0.5", "192.168.1.5"},
+			HostIP:         "10.0.0.1",
+			Labels:         map[string]string{"app": "web"},
+			Annotations:    map[string]string{"example.com/team": "frontend"},
+			Networks:       map[string]string{"192.168.1.5": "shop/macvlan"},
+			Ports:          []InventoryPort{{Name: "http", Port: 8080, Protocol: "TCP"}},
+			Owner:          &Owner{Type: "Deployment", Name: "web"},
+			ImmediateOwner: &Owner{Type: "ReplicaSet", Name: "web-5d8f7"},
+		}},
+		Nodes: []InventoryObject{{
+			Name:   "node-1",
+			IPs:    []string{"10.0.0.1", "192.168.10.1"},
+			Labels: map[string]string{"topology.kubernetes.io/zone": "us-east-1a"},
+		}},
+		Services: []InventoryObject{{
+			Name:      "web",
+			Namespace: "shop",
+			IPs:       []string{"172.30.0.10"},
+		}},
+		Namespaces: []InventoryObject{{
+			Name:   "shop",
+			Labels: map[string]string{"cost-center": "1234"},
+		}},
+	}, inventory)
+}
@@ -1,13 +1,16 @@
 package kubernetes
 
-import "github.com/stretchr/testify/mock"
+import (
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/stretchr/testify/mock"
+)
 
 type KubeDataMock struct {
 	mock.Mock
 	kubeDataInterface
 }
 
-func (o *KubeDataMock) InitFromConfig(kubeConfigPath string) error {
-	args := o.Called(kubeConfigPath)
+func (o *KubeDataMock) InitFromConfig(kubeConfigPath string, cfg *api.NetworkTransformKubernetes) error {
+	args := o.Called(kubeConfigPath, cfg)
 	return args.Error(0)
 }
@@ -23,13 +23,15 @@ import (
 	"net"
 	"os"
 	"path"
+	"sort"
 	"time"
 
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
 	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/kubernetes/cni"
 	log "github.com/sirupsen/logrus"
-	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -46,11 +48,17 @@ const (
 	typeNode              = "Node"
 	typePod               = "Pod"
 	typeService           = "Service"
+	replicaSetKind        = "apps/ReplicaSet"
+	// defaultOwnerDepth is used when the owner depth isn't configured: Pod to ReplicaSet to Deployment
+	defaultOwnerDepth = 2
 )
 
 type kubeDataInterface interface {
-	GetInfo(string) (*Info, error)
-	InitFromConfig(string) error
+	// GetInfo returns the object owning the IP at the given time. A zero time means now.
+	GetInfo(string, time.Time) (*Info, error)
+	// GetServiceEndpoints relates an IP, port and protocol to Services
+	GetServiceEndpoints(string, int, string) (*ServiceEndpoints, error)
+	InitFromConfig(string, *api.NetworkTransformKubernetes) error
 }
 
 type KubeData struct {
@@ -59,17 +67,37 @@ type KubeData struct {
 	pods     cache.SharedIndexInformer
 	nodes    cache.SharedIndexInformer
 	services cache.SharedIndexInformer
-	// replicaSets caches the ReplicaSets as partially-filled *ObjectMeta pointers
-	replicaSets cache.SharedIndexInformer
-	stopChan    chan struct{}
+	// owners cache the intermediate owners (ReplicaSets, Jobs...) as partially-filled *ObjectMeta pointers,
+	// indexed by their group and kind (e.g. apps/ReplicaSet)
+	owners map[string]cache.SharedIndexInformer
+	// namespaces caches the Namespaces as *ObjectMeta pointers holding only the allow-listed labels.
+	// It is nil when no namespace labels are configured.
+	namespaces cache.SharedIndexInformer
+	// endpointSlices caches the EndpointSlices as *endpointSlice pointers. It is nil when Service
+	// endpoints aren't configured.
+	endpointSlices      cache.SharedIndexInformer
+	watchEndpointSlices bool
+	// ownerDepth is the maximum number of owner references followed to find the top-level owner
+	ownerDepth int
+	// allOwnerKinds is set when the owner depth is configured: then, all the ownerInformers kinds are followed.
+	// Otherwise, only ReplicaSets are.
+	allOwnerKinds   bool
+	namespaceLabels []string
+	podAnnotations  []string
+	nodeLabels      []string
+	cniPlugins      []cni.Plugin
+	// history keeps the IPs of the deleted Pods. It is nil when disabled.
+	history  *ipHistory
+	stopChan chan struct{}
 }
 
 type Owner struct {
-	Type string
-	Name string
+	Type string `yaml:"type" json:"type"`
+	Name string `yaml:"name" json:"name"`
 }
 
 // Info contains precollected metadata for Pods, Nodes and Services.
+// Owner is the top-level owner, while ImmediateOwner is the one from the object's own owner references.
 // Not all the fields are populated for all the above types. To save
 // memory, we just keep in memory the necessary data for each Type.
 // For more information about which fields are set for each type, please
@@ -77,11 +105,25 @@ type Owner struct {
 type Info struct {
 	// Informers need that internal object is an ObjectMeta instance
 	metav1.ObjectMeta
-	Type     string
-	Owner    Owner
-	HostName string
-	HostIP   string
-	ips      []string
+	Type           string
+	Owner          Owner
+	ImmediateOwner Owner
+	HostName       string
+	HostIP         string
+	// NamespaceLabels and NodeLabels only contain the allow-listed labels. They are set
+	// on the copy returned by GetInfo, since they can change independently of the object.
+	NamespaceLabels map[string]string
+	NodeLabels      map[string]string
+	// Network is the name of the secondary network of the looked up IP, if any. It is also set on the
+	// copy returned by GetInfo, from networks, which maps the secondary network IPs to their network name.
+	Network  string
+	networks map[string]string
+	// FromHistory is set on the copy returned by GetInfo when the IP was owned by a deleted object
+	// at the requested time
+	FromHistory bool
+	ips         []string
+	// ports are the ports of Services, or the named container ports of Pods
+	ports []servicePort
 }
 
 var commonIndexers = map[string]cache.IndexFunc{
@@ -90,17 +132,44 @@ var commonIndexers = map[string]cache.IndexFunc{
 	},
 }
 
-func (k *KubeData) GetInfo(ip string) (*Info, error) {
-	if info, ok := k.fetchInformers(ip); ok {
-		// Owner data might be discovered after the owned, so we fetch it
-		// at the last moment
-		if info.Owner.Name == "" {
-			info.Owner = k.getOwner(info)
+func (k *KubeData) GetInfo(ip string, timestamp time.Time) (*Info, error) {
+	info, ok := k.fetchInformers(ip)
+	fromHistory := false
+	// the IP might have been reassigned since the requested time
+	if k.history != nil && !timestamp.IsZero() && (!ok || timestamp.Before(info.CreationTimestamp.Time)) {
+		if past, found := k.history.lookup(ip, timestamp); found {
+			info, ok, fromHistory = past, true, true
 		}
-		return info, nil
 	}
+	if !ok {
+		return nil, fmt.Errorf("informers can't find IP %s", ip)
+	}
+	// Owner data might be discovered after the owned, so we fetch it
+	// at the last moment
+	if info.Owner.Name == "" {
+		info.ImmediateOwner, info.Owner = k.getOwners(info)
+	}
+	if !fromHistory && k.namespaces == nil && len(k.nodeLabels) == 0 {
+		return withNetwork(info, ip), nil
+	}
+	enriched := *info
+	enriched.NamespaceLabels = k.getNamespaceLabels(info.Namespace)
+	enriched.NodeLabels = k.getNodeLabels(info.HostName)
+	enriched.Network = info.networks[ip]
+	enriched.FromHistory = fromHistory
+	return &enriched, nil
+}
 
-	return nil, fmt.Errorf("informers can't find IP %s", ip)
+// withNetwork returns a copy of the Info with the secondary network of the IP, or the same Info if
+// the IP doesn't belong to a secondary network
+func withNetwork(info *Info, ip string) *Info {
+	network, ok := info.networks[ip]
+	if !ok {
+		return info
+	}
+	withNetwork := *info
+	withNetwork.Network = network
+	return &withNetwork
 }
 
 func (k *KubeData) fetchInformers(ip string) (*Info, bool) {
@@ -120,6 +189,26 @@ func (k *KubeData) fetchInformers(ip string) (*Info, bool) {
 	return nil, false
 }
 
+// onPodDeleted records the IPs of the deleted Pod in the history. Its host name and owners are
+// resolved now, since the Node and owners might be deleted too by the time the history is looked up.
+func (k *KubeData) onPodDeleted(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	info, ok := obj.(*Info)
+	if !ok || len(info.ips) == 0 {
+		return
+	}
+	past := *info
+	if past.HostName == "" {
+		past.HostName = k.getHostName(past.HostIP)
+	}
+	if past.Owner.Name == "" {
+		past.ImmediateOwner, past.Owner = k.getOwners(&past)
+	}
+	k.history.add(&past, time.Now())
+}
+
 func infoForIP(idx cache.Indexer, ip string) (*Info, bool) {
 	objs, err := idx.ByIndex(IndexIP, ip)
 	if err != nil {
@@ -132,35 +221,109 @@ func infoForIP(idx cache.Indexer, ip string) (*Info, bool) {
 	return objs[0].(*Info), true
 }
 
-func (k *KubeData) getOwner(info *Info) Owner {
-	if len(info.OwnerReferences) != 0 {
-		ownerReference := info.OwnerReferences[0]
-		if ownerReference.Kind != "ReplicaSet" {
-			return Owner{
-				Name: ownerReference.Name,
-				Type: ownerReference.Kind,
-			}
+// getOwners returns the immediate and the top-level owners, which are found by following the controller
+// owner references through the owners informers, up to ownerDepth references
+func (k *KubeData) getOwners(info *Info) (immediate Owner, top Owner) {
+	ref := controllerRef(info.OwnerReferences)
+	if ref == nil {
+		// If no owner references found, return itself as owner
+		self := Owner{
+			Name: info.Name,
+			Type: info.Type,
 		}
-
-		item, ok, err := k.replicaSets.GetIndexer().GetByKey(info.Namespace + "/" + ownerReference.Name)
+		return self, self
+	}
+	immediate = Owner{Name: ref.Name, Type: ref.Kind}
+	top = immediate
+	for depth := 1; depth < k.ownerDepth; depth++ {
+		informer, ok := k.owners[groupKind(ref)]
+		if !ok {
+			// the owner is not a kind we know about (e.g. a custom resource): it is the top-level one
+			break
+		}
+		key := info.Namespace + "/" + ref.Name
+		item, ok, err := informer.GetIndexer().GetByKey(key)
 		if err != nil {
-			log.WithError(err).WithField("key", info.Namespace+"/"+ownerReference.Name).
-				Debug("can't get ReplicaSet info from informer. Ignoring")
-		} else if ok {
-			rsInfo := item.(*metav1.ObjectMeta)
-			if len(rsInfo.OwnerReferences) > 0 {
-				return Owner{
-					Name: rsInfo.OwnerReferences[0].Name,
-					Type: rsInfo.OwnerReferences[0].Kind,
-				}
-			}
+			log.WithError(err).WithField("key", key).
+				Debugf("can't get %s info from informer. Ignoring", ref.Kind)
+			break
+		}
+		if !ok {
+			break
+		}
+		if ref = controllerRef(item.(*metav1.ObjectMeta).OwnerReferences); ref == nil {
+			break
 		}
+		top = Owner{Name: ref.Name, Type: ref.Kind}
+	}
+	return immediate, top
+}
+
+// controllerRef returns the managing controller reference or, if there is none, the first reference
+func controllerRef(refs []metav1.OwnerReference) *metav1.OwnerReference {
+	if len(refs) == 0 {
+		return nil
+	}
+	for i := range refs {
+		if refs[i].Controller != nil && *refs[i].Controller {
+			return &refs[i]
+		}
+	}
+	return &refs[0]
+}
+
+func groupKind(ref *metav1.OwnerReference) string {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return ref.Kind
+	}
+	return gv.Group + "/" + ref.Kind
+}
+
+func (k *KubeData) getNamespaceLabels(namespace string) map[string]string {
+	if k.namespaces == nil || namespace == "" {
+		return nil
+	}
+	item, ok, err := k.namespaces.GetIndexer().GetByKey(namespace)
+	if err != nil {
+		log.WithError(err).WithField("key", namespace).
+			Debug("can't get Namespace info from informer. Ignoring")
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+	return item.(*metav1.ObjectMeta).Labels
+}
+
+func (k *KubeData) getNodeLabels(hostName string) map[string]string {
+	if len(k.nodeLabels) == 0 || hostName == "" {
+		return nil
+	}
+	item, ok, err := k.nodes.GetIndexer().GetByKey(hostName)
+	if err != nil {
+		log.WithError(err).WithField("key", hostName).
+			Debug("can't get Node info from informer. Ignoring")
+		return nil
+	}
+	if !ok {
+		return nil
 	}
-	// If no owner references found, return itself as owner
-	return Owner{
-		Name: info.Name,
-		Type: info.Type,
+	return filterKeys(item.(*Info).Labels, k.nodeLabels)
+}
+
+// filterKeys returns the entries whose keys are in the allowed list, or nil if there are none
+func filterKeys(m map[string]string, allowed []string) map[string]string {
+	var filtered map[string]string
+	for _, key := range allowed {
+		if value, ok := m[key]; ok {
+			if filtered == nil {
+				filtered = map[string]string{}
+			}
+			filtered[key] = value
+		}
 	}
+	return filtered
 }
 
 func (k *KubeData) getHostName(hostIP string) string {
@@ -193,7 +356,7 @@ func (k *KubeData) initNodeInformer(informerFactory informers.SharedInformerFact
 			}
 		}
 		// CNI-dependent logic (must work regardless of whether the CNI is installed)
-		ips = cni.AddOvnIPs(ips, node)
+		ips = cni.AddNodeIPs(ips, node, k.cniPlugins)
 
 		return &Info{
 			ObjectMeta: metav1.ObjectMeta{
@@ -235,16 +398,47 @@ func (k *KubeData) initPodInformer(informerFactory informers.SharedInformerFacto
 				ips = append(ips, ip.IP)
 			}
 		}
+		// secondary networks (e.g. attached by Multus) are indexed too
+		networks, err := cni.SecondaryNetworkIPs(pod)
+		if err != nil {
+			// Log the error as Info, do not block other ips indexing
+			log.Infof("failed to index secondary network IPs for pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+		secondaryIPs := make([]string, 0, len(networks))
+		for ip := range networks {
+			secondaryIPs = append(secondaryIPs, ip)
+		}
+		sort.Strings(secondaryIPs)
+		ips = append(ips, secondaryIPs...)
+		// named ports are kept to resolve the NetworkPolicies ports
+		var ports []servicePort
+		for i := range pod.Spec.Containers {
+			for _, p := range pod.Spec.Containers[i].Ports {
+				if p.Name == "" {
+					continue
+				}
+				protocol := string(p.Protocol)
+				if protocol == "" {
+					protocol = string(v1.ProtocolTCP)
+				}
+				ports = append(ports, servicePort{name: p.Name, port: int(p.ContainerPort), protocol: protocol})
+			}
+		}
 		return &Info{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:            pod.Name,
 				Namespace:       pod.Namespace,
 				Labels:          pod.Labels,
+				Annotations:     filterKeys(pod.Annotations, k.podAnnotations),
 				OwnerReferences: pod.OwnerReferences,
+				// the creation time is the start of the validity of the IPs, for the history
+				CreationTimestamp: pod.CreationTimestamp,
 			},
-			Type:   typePod,
-			HostIP: pod.Status.HostIP,
-			ips:    ips,
+			Type:     typePod,
+			HostIP:   pod.Status.HostIP,
+			networks: networks,
+			ips:      ips,
+			ports:    ports,
 		}, nil
 	}); err != nil {
 		return fmt.Errorf("can't set pods transform: %w", err)
@@ -252,6 +446,9 @@ func (k *KubeData) initPodInformer(informerFactory informers.SharedInformerFacto
 	if err := pods.AddIndexers(commonIndexers); err != nil {
 		return fmt.Errorf("can't add %s indexer to Pods informer: %w", IndexIP, err)
 	}
+	if k.history != nil {
+		pods.AddEventHandler(cache.ResourceEventHandlerFuncs{DeleteFunc: k.onPodDeleted})
+	}
 
 	k.pods = pods
 	return nil
@@ -269,14 +466,19 @@ func (k *KubeData) initServiceInformer(informerFactory informers.SharedInformerF
 		if svc.Spec.ClusterIP == v1.ClusterIPNone {
 			return nil, errors.New("not indexing service without ClusterIP")
 		}
+		ports := make([]servicePort, 0, len(svc.Spec.Ports))
+		for _, p := range svc.Spec.Ports {
+			ports = append(ports, servicePort{name: p.Name, port: int(p.Port), protocol: string(p.Protocol)})
+		}
 		return &Info{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      svc.Name,
 				Namespace: svc.Namespace,
 				Labels:    svc.Labels,
 			},
-			Type: typeService,
-			ips:  svc.Spec.ClusterIPs,
+			Type:  typeService,
+			ips:   svc.Spec.ClusterIPs,
+			ports: ports,
 		}, nil
 	}); err != nil {
 		return fmt.Errorf("can't set services transform: %w", err)
@@ -289,29 +491,84 @@ func (k *KubeData) initServiceInformer(informerFactory informers.SharedInformerF
 	return nil
 }
 
-func (k *KubeData) initReplicaSetInformer(informerFactory informers.SharedInformerFactory) error {
-	k.replicaSets = informerFactory.Apps().V1().ReplicaSets().Informer()
-	// To save space, instead of storing a complete *appvs1.Replicaset instance, the
-	// informer's cache will store a *metav1.ObjectMeta with the minimal required fields
-	if err := k.replicaSets.SetTransform(func(i interface{}) (interface{}, error) {
-		rs, ok := i.(*appsv1.ReplicaSet)
+func (k *KubeData) initNamespaceInformer(informerFactory informers.SharedInformerFactory) error {
+	namespaces := informerFactory.Core().V1().Namespaces().Informer()
+	// To save space, instead of storing a complete *v1.Namespace instance, the informer's
+	// cache will store a *metav1.ObjectMeta with only the allow-listed labels
+	if err := namespaces.SetTransform(func(i interface{}) (interface{}, error) {
+		ns, ok := i.(*v1.Namespace)
 		if !ok {
-			return nil, fmt.Errorf("was expecting a ReplicaSet. Got: %T", i)
+			return nil, fmt.Errorf("was expecting a Namespace. Got: %T", i)
 		}
 		return &metav1.ObjectMeta{
-			Name:            rs.Name,
-			Namespace:       rs.Namespace,
-			OwnerReferences: rs.OwnerReferences,
+			Name:   ns.Name,
+			Labels: filterKeys(ns.Labels, k.namespaceLabels),
 		}, nil
 	}); err != nil {
-		return fmt.Errorf("can't set ReplicaSets transform: %w", err)
+		return fmt.Errorf("can't set namespaces transform: %w", err)
+	}
+	k.namespaces = namespaces
+	return nil
+}
+
+// ownerInformers create the informers for the kinds that can own Pods and be owned themselves
+var ownerInformers = map[string]func(informers.SharedInformerFactory) cache.SharedIndexInformer{
+	replicaSetKind: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Apps().V1().ReplicaSets().Informer()
+	},
+	"apps/Deployment": func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Apps().V1().Deployments().Informer()
+	},
+	"apps/StatefulSet": func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Apps().V1().StatefulSets().Informer()
+	},
+	"apps/DaemonSet": func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Apps().V1().DaemonSets().Informer()
+	},
+	"batch/Job": func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+		return f.Batch().V1().Jobs().Informer()
+	},
+}
+
+// initOwnerInformers creates the informers for the owner kinds to follow, when owner references need to
+// be followed (i.e. when the owner depth is greater than 1). When the owner depth isn't configured, only
+// ReplicaSets are watched, so that only the ReplicaSet owners of Pods are followed.
+func (k *KubeData) initOwnerInformers(informerFactory informers.SharedInformerFactory) error {
+	k.owners = map[string]cache.SharedIndexInformer{}
+	if k.ownerDepth <= 1 {
+		return nil
+	}
+	for groupKind, newInformer := range ownerInformers {
+		if !k.allOwnerKinds && groupKind != replicaSetKind {
+			continue
+		}
+		informer := newInformer(informerFactory)
+		// To save space, instead of storing complete instances, the informer's cache
+		// will store a *metav1.ObjectMeta with the minimal required fields
+		if err := informer.SetTransform(func(i interface{}) (interface{}, error) {
+			obj, ok := i.(metav1.Object)
+			if !ok {
+				return nil, fmt.Errorf("was expecting a Kubernetes object. Got: %T", i)
+			}
+			return &metav1.ObjectMeta{
+				Name:            obj.GetName(),
+				Namespace:       obj.GetNamespace(),
+				OwnerReferences: obj.GetOwnerReferences(),
+			}, nil
+		}); err != nil {
+			return fmt.Errorf("can't set %s transform: %w", groupKind, err)
+		}
+		k.owners[groupKind] = informer
 	}
 	return nil
 }
 
-func (k *KubeData) InitFromConfig(kubeConfigPath string) error {
+func (k *KubeData) InitFromConfig(kubeConfigPath string, cfg *api.NetworkTransformKubernetes) error {
 	// Initialization variables
 	k.stopChan = make(chan struct{})
+	if err := k.setConfig(cfg); err != nil {
+		return err
+	}
 
 	config, err := LoadConfig(kubeConfigPath)
 	if err != nil {
@@ -331,6 +588,30 @@ func (k *KubeData) InitFromConfig(kubeConfigPath string) error {
 	return nil
 }
 
+func (k *KubeData) setConfig(cfg *api.NetworkTransformKubernetes) error {
+	if depth := cfg.GetOwnerDepth(); depth > 0 {
+		k.ownerDepth = depth
+		k.allOwnerKinds = true
+	} else {
+		k.ownerDepth = defaultOwnerDepth
+		k.allOwnerKinds = false
+	}
+	if size := cfg.GetIPHistorySize(); size > 0 {
+		k.history = newIPHistory(size, cfg.GetIPHistoryMaxAge())
+	}
+	var cniPlugins []string
+	if cfg != nil {
+		k.namespaceLabels = cfg.NamespaceLabels
+		k.podAnnotations = cfg.PodAnnotations
+		k.nodeLabels = cfg.NodeLabels
+		k.watchEndpointSlices = cfg.ServiceEndpoints != nil
+		cniPlugins = cfg.CNIPlugins
+	}
+	var err error
+	k.cniPlugins, err = cni.GetPlugins(cniPlugins)
+	return err
+}
+
 func LoadConfig(kubeConfigPath string) (*rest.Config, error) {
 	// if no config path is provided, load it from the env variable
 	if kubeConfigPath == "" {
@@ -372,10 +653,24 @@ func (k *KubeData) initInformers(client kubernetes.Interface) error {
 	if err != nil {
 		return err
 	}
-	err = k.initReplicaSetInformer(informerFactory)
+	err = k.initOwnerInformers(informerFactory)
 	if err != nil {
 		return err
 	}
+	// the namespaces informer is only needed, and requires permissions, when namespace labels are configured
+	if len(k.namespaceLabels) > 0 {
+		err = k.initNamespaceInformer(informerFactory)
+		if err != nil {
+			return err
+		}
+	}
+
+	if k.watchEndpointSlices {
+		err = k.initEndpointSliceInformer(informerFactory)
+		if err != nil {
+			return err
+		}
+	}
 
 	log.Debugf("starting kubernetes informers, waiting for syncronization")
 	informerFactory.Start(k.stopChan)
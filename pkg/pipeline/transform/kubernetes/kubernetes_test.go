@@ -18,11 +18,18 @@
 package kubernetes
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -80,7 +87,7 @@ func TestGetInfoPods(t *testing.T) {
 
 	kubeData.pods = &pim
 	kubeData.nodes = &him
-	info, err := kubeData.GetInfo("1.2.3.4")
+	info, err := kubeData.GetInfo("1.2.3.4", time.Time{})
 	require.NoError(t, err)
 
 	require.Equal(t, *info, Info{
@@ -94,3 +101,190 @@ func TestGetInfoPods(t *testing.T) {
 		Owner:    Owner{Name: "podName", Type: "Pod"},
 	})
 }
+
+func ownerRef(apiVersion, kind, name string) []metav1.OwnerReference {
+	controller := true
+	return []metav1.OwnerReference{{APIVersion: apiVersion, Kind: kind, Name: name, Controller: &controller}}
+}
+
+func ownersInformer(t *testing.T, objs ...*metav1.ObjectMeta) cache.SharedIndexInformer {
+	idx := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, obj := range objs {
+		require.NoError(t, idx.Add(obj))
+	}
+	im := InformerMock{}
+	im.On("GetIndexer").Return(idx)
+	return &im
+}
+
+func TestGetOwners(t *testing.T) {
+	kubeData := KubeData{
+		ownerDepth: 3,
+		owners: map[string]cache.SharedIndexInformer{
+			"apps/ReplicaSet": ownersInformer(t, &metav1.ObjectMeta{
+				Name: "web-5d8f7", Namespace: "ns", OwnerReferences: ownerRef("apps/v1", "Deployment", "web"),
+			}),
+			"apps/Deployment": ownersInformer(t, &metav1.ObjectMeta{
+				Name: "web", Namespace: "ns", OwnerReferences: ownerRef("example.com/v1", "WebApp", "my-app"),
+			}),
+			"batch/Job": ownersInformer(t, &metav1.ObjectMeta{
+				Name: "backup-27800000", Namespace: "ns", OwnerReferences: ownerRef("batch/v1", "CronJob", "backup"),
+			}),
+		},
+	}
+	pod := func(refs []metav1.OwnerReference) *Info {
+		return &Info{Type: typePod, ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns", OwnerReferences: refs}}
+	}
+
+	// Pod -> ReplicaSet -> Deployment -> custom resource
+	immediate, top := kubeData.getOwners(pod(ownerRef("apps/v1", "ReplicaSet", "web-5d8f7")))
+	require.Equal(t, Owner{Type: "ReplicaSet", Name: "web-5d8f7"}, immediate)
+	require.Equal(t, Owner{Type: "WebApp", Name: "my-app"}, top)
+
+	// Pod -> Job -> CronJob
+	immediate, top = kubeData.getOwners(pod(ownerRef("batch/v1", "Job", "backup-27800000")))
+	require.Equal(t, Owner{Type: "Job", Name: "backup-27800000"}, immediate)
+	require.Equal(t, Owner{Type: "CronJob", Name: "backup"}, top)
+
+	// owner not found in informers
+	immediate, top = kubeData.getOwners(pod(ownerRef("apps/v1", "StatefulSet", "db")))
+	require.Equal(t, Owner{Type: "StatefulSet", Name: "db"}, immediate)
+	require.Equal(t, immediate, top)
+
+	// kind with the same name from another group is not followed
+	immediate, top = kubeData.getOwners(pod(ownerRef("example.com/v1", "ReplicaSet", "web-5d8f7")))
+	require.Equal(t, immediate, top)
+
+	// no owner
+	immediate, top = kubeData.getOwners(pod(nil))
+	require.Equal(t, Owner{Type: typePod, Name: "pod"}, immediate)
+	require.Equal(t, immediate, top)
+
+	// limited depth
+	kubeData.ownerDepth = 2
+	_, top = kubeData.getOwners(pod(ownerRef("apps/v1", "ReplicaSet", "web-5d8f7")))
+	require.Equal(t, Owner{Type: "Deployment", Name: "web"}, top)
+	kubeData.ownerDepth = 1
+	immediate, top = kubeData.getOwners(pod(ownerRef("apps/v1", "ReplicaSet", "web-5d8f7")))
+	require.Equal(t, Owner{Type: "ReplicaSet", Name: "web-5d8f7"}, top)
+	require.Equal(t, immediate, top)
+}
+
+func TestInitOwnerInformers(t *testing.T) {
+	// startedInformers returns the types of the informers registered in the factory
+	startedInformers := func(cfg *api.NetworkTransformKubernetes) map[reflect.Type]bool {
+		kubeData := KubeData{}
+		require.NoError(t, kubeData.setConfig(cfg))
+		factory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+		require.NoError(t, kubeData.initOwnerInformers(factory))
+		stop := make(chan struct{})
+		defer close(stop)
+		factory.Start(stop)
+		return factory.WaitForCacheSync(stop)
+	}
+
+	// by default, only ReplicaSets are watched, as they were before the owner depth was configurable
+	require.Equal(t, map[reflect.Type]bool{
+		reflect.TypeOf(&appsv1.ReplicaSet{}): true,
+	}, startedInformers(nil))
+	require.Equal(t, map[reflect.Type]bool{
+		reflect.TypeOf(&appsv1.ReplicaSet{}): true,
+	}, startedInformers(&api.NetworkTransformKubernetes{}))
+
+	require.Equal(t, map[reflect.Type]bool{
+		reflect.TypeOf(&appsv1.ReplicaSet{}):  true,
+		reflect.TypeOf(&appsv1.Deployment{}):  true,
+		reflect.TypeOf(&appsv1.StatefulSet{}): true,
+		reflect.TypeOf(&appsv1.DaemonSet{}):   true,
+		reflect.TypeOf(&batchv1.Job{}):        true,
+	}, startedInformers(&api.NetworkTransformKubernetes{OwnerDepth: 3}))
+
+	require.Empty(t, startedInformers(&api.NetworkTransformKubernetes{OwnerDepth: 1}))
+}
+
+func TestGetOwners_DefaultDepth(t *testing.T) {
+	kubeData := KubeData{}
+	require.NoError(t, kubeData.setConfig(nil))
+	kubeData.owners = map[string]cache.SharedIndexInformer{
+		"apps/ReplicaSet": ownersInformer(t, &metav1.ObjectMeta{
+			Name: "web-5d8f7", Namespace: "ns", OwnerReferences: ownerRef("apps/v1", "Deployment", "web"),
+		}),
+	}
+	pod := func(refs []metav1.OwnerReference) *Info {
+		return &Info{Type: typePod, ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns", OwnerReferences: refs}}
+	}
+
+	// Pod -> ReplicaSet -> Deployment
+	_, top := kubeData.getOwners(pod(ownerRef("apps/v1", "ReplicaSet", "web-5d8f7")))
+	require.Equal(t, Owner{Type: "Deployment", Name: "web"}, top)
+
+	// Jobs are not followed to their CronJob
+	_, top = kubeData.getOwners(pod(ownerRef("batch/v1", "Job", "backup-27800000")))
+	require.Equal(t, Owner{Type: "Job", Name: "backup-27800000"}, top)
+}
+
+func TestGetInfoEnrichment(t *testing.T) {
+	newInformer := func(indexers cache.Indexers, objs ...interface{}) cache.SharedIndexInformer {
+		idx := cache.NewIndexer(cache.MetaNamespaceKeyFunc, indexers)
+		for _, obj := range objs {
+			require.NoError(t, idx.Add(obj))
+		}
+		im := InformerMock{}
+		im.On("GetIndexer").Return(idx)
+		return &im
+	}
+	pod := &Info{
+		Type: typePod,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "podName",
+			Namespace:   "podNamespace",
+			Annotations: map[string]string{"team": "network"},
+		},
+		HostIP: "10.0.0.1",
+		ips:    []string{"1.2.3.4"},
+	}
+	kubeData := KubeData{
+		ownerDepth: 1,
+		nodeLabels: []string{"topology.kubernetes.io/zone", "not-found"},
+		pods:       newInformer(commonIndexers, pod),
+		nodes: newInformer(commonIndexers, &Info{
+			Type: typeNode,
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "nodeName",
+				Labels: map[string]string{"topology.kubernetes.io/zone": "us-east-1a", "kubernetes.io/os": "linux"},
+			},
+			HostIP:   "10.0.0.1",
+			HostName: "nodeName",
+			ips:      []string{"10.0.0.1"},
+		}),
+		services: newInformer(commonIndexers),
+		namespaces: newInformer(cache.Indexers{}, &metav1.ObjectMeta{
+			Name:   "podNamespace",
+			Labels: map[string]string{"cost-center": "1234"},
+		}),
+	}
+
+	info, err := kubeData.GetInfo("1.2.3.4", time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, "nodeName", info.HostName)
+	require.Equal(t, map[string]string{"cost-center": "1234"}, info.NamespaceLabels)
+	require.Equal(t, map[string]string{"topology.kubernetes.io/zone": "us-east-1a"}, info.NodeLabels)
+	require.Equal(t, map[string]string{"team": "network"}, info.Annotations)
+	// the cached object isn't modified
+	require.Nil(t, pod.NamespaceLabels)
+	require.Nil(t, pod.NodeLabels)
+
+	info, err = kubeData.GetInfo("10.0.0.1", time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, typeNode, info.Type)
+	require.Nil(t, info.NamespaceLabels)
+	require.Equal(t, map[string]string{"topology.kubernetes.io/zone": "us-east-1a"}, info.NodeLabels)
+}
+
+func TestFilterKeys(t *testing.T) {
+	m := map[string]string{"a": "1", "b": "2"}
+	require.Equal(t, map[string]string{"a": "1"}, filterKeys(m, []string{"a", "c"}))
+	require.Nil(t, filterKeys(m, []string{"c"}))
+	require.Nil(t, filterKeys(m, nil))
+	require.Nil(t, filterKeys(nil, []string{"a"}))
+}
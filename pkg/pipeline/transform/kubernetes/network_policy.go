@@ -0,0 +1,384 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package kubernetes
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	PolicyAllow = "allow"
+	PolicyDeny  = "deny"
+)
+
+// PolicyResult is the verdict of the NetworkPolicies for a flow. Policies are the namespace/name of the
+// policies allowing the flow or, when it's denied, of the policies isolating the Pod denying it.
+type PolicyResult struct {
+	Verdict  string
+	Policies []string
+}
+
+// PodSource looks up the objects owning IPs, such as the live cluster data or an inventory
+type PodSource interface {
+	GetInfo(string, time.Time) (*Info, error)
+}
+
+// PolicyEvaluator keeps an in-memory model of the NetworkPolicies to evaluate flows against them. Pods are
+// looked up through the PodSource of the cluster, while the policies and the namespaces, whose labels are all
+// needed to match the namespace selectors, are watched by its own informers.
+type PolicyEvaluator struct {
+	pods       PodSource
+	policies   cache.SharedIndexInformer
+	namespaces cache.SharedIndexInformer
+	stopChan   chan struct{}
+}
+
+// networkPolicy is the compiled form of a NetworkPolicy, kept in the informer's cache
+type networkPolicy struct {
+	// Informers need that internal object is an ObjectMeta instance
+	metav1.ObjectMeta
+	podSelector     labels.Selector
+	isolatesIngress bool
+	isolatesEgress  bool
+	ingress         []policyRule
+	egress          []policyRule
+}
+
+// policyRule allows the traffic from (ingress) or to (egress) any of its peers, on any of its ports.
+// Empty peers or ports match everything.
+type policyRule struct {
+	peers []policyPeer
+	ports []policyPort
+}
+
+// policyPeer is either an IP block, or Pods selected by their labels and their namespace labels. A nil
+// podSelector selects all the Pods, while a nil namespaceSelector selects the policy namespace.
+type policyPeer struct {
+	ipBlock           *net.IPNet
+	except            []*net.IPNet
+	podSelector       labels.Selector
+	namespaceSelector labels.Selector
+}
+
+// policyPort matches a port, or a port range when endPort is set. A 0 port matches all the ports.
+// Named ports are resolved against the container ports of the destination Pod.
+type policyPort struct {
+	protocol  string
+	port      int
+	endPort   int
+	namedPort string
+}
+
+// NewPolicyEvaluator watches the NetworkPolicies and namespaces of the cluster, whose Pods are looked up in pods
+func NewPolicyEvaluator(client kubernetes.Interface, pods PodSource) (*PolicyEvaluator, error) {
+	e := &PolicyEvaluator{pods: pods, stopChan: make(chan struct{})}
+	// using a distinct factory, since the namespaces informer from KubeData only keeps the allow-listed labels
+	informerFactory := informers.NewSharedInformerFactory(client, syncTime)
+	policies := informerFactory.Networking().V1().NetworkPolicies().Informer()
+	if err := policies.SetTransform(func(i interface{}) (interface{}, error) {
+		policy, ok := i.(*networkingv1.NetworkPolicy)
+		if !ok {
+			return nil, fmt.Errorf("was expecting a NetworkPolicy. Got: %T", i)
+		}
+		return compileNetworkPolicy(policy)
+	}); err != nil {
+		return nil, fmt.Errorf("can't set NetworkPolicies transform: %w", err)
+	}
+	namespaces := informerFactory.Core().V1().Namespaces().Informer()
+	if err := namespaces.SetTransform(func(i interface{}) (interface{}, error) {
+		ns, ok := i.(*v1.Namespace)
+		if !ok {
+			return nil, fmt.Errorf("was expecting a Namespace. Got: %T", i)
+		}
+		return &metav1.ObjectMeta{Name: ns.Name, Labels: ns.Labels}, nil
+	}); err != nil {
+		return nil, fmt.Errorf("can't set namespaces transform: %w", err)
+	}
+	e.policies = policies
+	e.namespaces = namespaces
+
+	log.Debugf("starting NetworkPolicies informers, waiting for syncronization")
+	informerFactory.Start(e.stopChan)
+	informerFactory.WaitForCacheSync(e.stopChan)
+	log.Debugf("NetworkPolicies informers started")
+	return e, nil
+}
+
+func compileNetworkPolicy(policy *networkingv1.NetworkPolicy) (*networkPolicy, error) {
+	podSelector, err := metav1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pod selector in NetworkPolicy %s/%s: %w", policy.Namespace, policy.Name, err)
+	}
+	np := &networkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      policy.Name,
+			Namespace: policy.Namespace,
+		},
+		podSelector: podSelector,
+	}
+	// without policy types, the policy isolates ingress, and egress only if it has egress rules
+	if len(policy.Spec.PolicyTypes) == 0 {
+		np.isolatesIngress = true
+		np.isolatesEgress = len(policy.Spec.Egress) > 0
+	}
+	for _, policyType := range policy.Spec.PolicyTypes {
+		switch policyType {
+		case networkingv1.PolicyTypeIngress:
+			np.isolatesIngress = true
+		case networkingv1.PolicyTypeEgress:
+			np.isolatesEgress = true
+		}
+	}
+	for i := range policy.Spec.Ingress {
+		rule, err := compilePolicyRule(policy.Spec.Ingress[i].From, policy.Spec.Ingress[i].Ports)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ingress rule in NetworkPolicy %s/%s: %w", policy.Namespace, policy.Name, err)
+		}
+		np.ingress = append(np.ingress, rule)
+	}
+	for i := range policy.Spec.Egress {
+		rule, err := compilePolicyRule(policy.Spec.Egress[i].To, policy.Spec.Egress[i].Ports)
+		if err != nil {
+			return nil, fmt.Errorf("invalid egress rule in NetworkPolicy %s/%s: %w", policy.Namespace, policy.Name, err)
+		}
+		np.egress = append(np.egress, rule)
+	}
+	return np, nil
+}
+
+func compilePolicyRule(peers []networkingv1.NetworkPolicyPeer, ports []networkingv1.NetworkPolicyPort) (policyRule, error) {
+	rule := policyRule{}
+	for i := range peers {
+		peer := policyPeer{}
+		if block := peers[i].IPBlock; block != nil {
+			_, cidr, err := net.ParseCIDR(block.CIDR)
+			if err != nil {
+				return rule, err
+			}
+			peer.ipBlock = cidr
+			for _, except := range block.Except {
+				_, exceptCIDR, err := net.ParseCIDR(except)
+				if err != nil {
+					return rule, err
+				}
+				peer.except = append(peer.except, exceptCIDR)
+			}
+		}
+		var err error
+		if peers[i].PodSelector != nil {
+			if peer.podSelector, err = metav1.LabelSelectorAsSelector(peers[i].PodSelector); err != nil {
+				return rule, err
+			}
+		}
+		if peers[i].NamespaceSelector != nil {
+			if peer.namespaceSelector, err = metav1.LabelSelectorAsSelector(peers[i].NamespaceSelector); err != nil {
+				return rule, err
+			}
+		}
+		rule.peers = append(rule.peers, peer)
+	}
+	for i := range ports {
+		port := policyPort{protocol: string(v1.ProtocolTCP)}
+		if ports[i].Protocol != nil {
+			port.protocol = string(*ports[i].Protocol)
+		}
+		if ports[i].Port != nil {
+			if ports[i].Port.Type == intstr.String {
+				port.namedPort = ports[i].Port.StrVal
+			} else {
+				port.port = int(ports[i].Port.IntVal)
+			}
+		}
+		if ports[i].EndPort != nil {
+			port.endPort = int(*ports[i].EndPort)
+		}
+		rule.ports = append(rule.ports, port)
+	}
+	return rule, nil
+}
+
+// Evaluate returns the NetworkPolicies verdict for a flow, or nil if neither the source nor the destination
+// are Pods. The flow must be allowed by the egress policies of the source Pod and by the ingress policies
+// of the destination Pod. The protocol is the Kubernetes one (TCP, UDP or SCTP), or empty if none of these.
+// Pods are looked up at the given time, a zero time meaning now.
+func (e *PolicyEvaluator) Evaluate(srcIP, dstIP string, dstPort int, protocol string, timestamp time.Time) *PolicyResult {
+	src, dst := e.lookupPod(srcIP, timestamp), e.lookupPod(dstIP, timestamp)
+	if src == nil && dst == nil {
+		return nil
+	}
+	var allowing []string
+	if src != nil {
+		isolating, allowed := e.evaluate(src, false, net.ParseIP(dstIP), dst, dstPort, protocol)
+		if len(isolating) > 0 && len(allowed) == 0 {
+			return &PolicyResult{Verdict: PolicyDeny, Policies: isolating}
+		}
+		allowing = append(allowing, allowed...)
+	}
+	if dst != nil {
+		isolating, allowed := e.evaluate(dst, true, net.ParseIP(srcIP), src, dstPort, protocol)
+		if len(isolating) > 0 && len(allowed) == 0 {
+			return &PolicyResult{Verdict: PolicyDeny, Policies: isolating}
+		}
+		allowing = append(allowing, allowed...)
+	}
+	sort.Strings(allowing)
+	return &PolicyResult{Verdict: PolicyAllow, Policies: allowing}
+}
+
+func (e *PolicyEvaluator) lookupPod(ip string, timestamp time.Time) *Info {
+	info, err := e.pods.GetInfo(ip, timestamp)
+	if err != nil || info.Type != typePod {
+		return nil
+	}
+	return info
+}
+
+// evaluate returns the policies isolating the Pod in the given direction, and those allowing the traffic
+// with the peer, which is described by its IP and, if it's a Pod, its Info
+func (e *PolicyEvaluator) evaluate(pod *Info, ingress bool, peerIP net.IP, peerPod *Info, port int, protocol string) (isolating, allowing []string) {
+	// the informers from the factory are indexed by namespace
+	objs, err := e.policies.GetIndexer().ByIndex(cache.NamespaceIndex, pod.Namespace)
+	if err != nil {
+		log.WithError(err).WithField("namespace", pod.Namespace).Debug("can't get NetworkPolicies from informer. Ignoring")
+		return nil, nil
+	}
+	podLabels := labels.Set(pod.Labels)
+	dstPod := peerPod
+	if ingress {
+		dstPod = pod
+	}
+	for _, obj := range objs {
+		policy := obj.(*networkPolicy)
+		isolates, rules := policy.isolatesEgress, policy.egress
+		if ingress {
+			isolates, rules = policy.isolatesIngress, policy.ingress
+		}
+		if !isolates || !policy.podSelector.Matches(podLabels) {
+			continue
+		}
+		name := policy.Namespace + "/" + policy.Name
+		isolating = append(isolating, name)
+		for i := range rules {
+			if e.ruleMatches(&rules[i], policy.Namespace, peerIP, peerPod, dstPod, port, protocol) {
+				allowing = append(allowing, name)
+				break
+			}
+		}
+	}
+	sort.Strings(isolating)
+	return isolating, allowing
+}
+
+func (e *PolicyEvaluator) ruleMatches(rule *policyRule, namespace string, peerIP net.IP, peerPod, dstPod *Info, port int, protocol string) bool {
+	portMatches := len(rule.ports) == 0
+	for i := range rule.ports {
+		if rule.ports[i].matches(port, protocol, dstPod) {
+			portMatches = true
+			break
+		}
+	}
+	if !portMatches {
+		return false
+	}
+	if len(rule.peers) == 0 {
+		return true
+	}
+	for i := range rule.peers {
+		if e.peerMatches(&rule.peers[i], namespace, peerIP, peerPod) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches checks the destination port and protocol. A named port only matches when the destination
+// is a Pod having a container port with this name and protocol.
+func (p *policyPort) matches(port int, protocol string, dstPod *Info) bool {
+	if p.protocol != protocol {
+		return false
+	}
+	if p.namedPort != "" {
+		if dstPod == nil {
+			return false
+		}
+		for i := range dstPod.ports {
+			if dstPod.ports[i].name == p.namedPort && dstPod.ports[i].protocol == protocol {
+				return dstPod.ports[i].port == port
+			}
+		}
+		return false
+	}
+	if p.port == 0 {
+		return true
+	}
+	if p.endPort == 0 {
+		return port == p.port
+	}
+	return port >= p.port && port <= p.endPort
+}
+
+func (e *PolicyEvaluator) peerMatches(peer *policyPeer, namespace string, peerIP net.IP, peerPod *Info) bool {
+	if peer.ipBlock != nil {
+		if peerIP == nil || !peer.ipBlock.Contains(peerIP) {
+			return false
+		}
+		for _, except := range peer.except {
+			if except.Contains(peerIP) {
+				return false
+			}
+		}
+		return true
+	}
+	if peerPod == nil {
+		return false
+	}
+	if peer.namespaceSelector == nil {
+		if peerPod.Namespace != namespace {
+			return false
+		}
+	} else if !peer.namespaceSelector.Matches(labels.Set(e.namespaceLabels(peerPod.Namespace))) {
+		return false
+	}
+	return peer.podSelector == nil || peer.podSelector.Matches(labels.Set(peerPod.Labels))
+}
+
+func (e *PolicyEvaluator) namespaceLabels(namespace string) map[string]string {
+	item, ok, err := e.namespaces.GetIndexer().GetByKey(namespace)
+	if err != nil {
+		log.WithError(err).WithField("key", namespace).
+			Debug("can't get Namespace info from informer. Ignoring")
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+	return item.(*metav1.ObjectMeta).Labels
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func policyPod(name, namespace, app, ip string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": app}},
+		Status:     v1.PodStatus{HostIP: "10.0.0.1", PodIPs: []v1.PodIP{{IP: ip}}},
+	}
+}
+
+func namedPortPod(name, namespace, ip, portName string, port int32) *v1.Pod {
+	pod := policyPod(name, namespace, name, ip)
+	pod.Spec.Containers = []v1.Container{{Name: "main", Ports: []v1.ContainerPort{{Name: portName, ContainerPort: port}}}}
+	return pod
+}
+
+func policyPorts(protocol v1.Protocol, port, endPort int32) []networkingv1.NetworkPolicyPort {
+	p := networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &intstr.IntOrString{IntVal: port}}
+	if endPort != 0 {
+		p.EndPort = &endPort
+	}
+	return []networkingv1.NetworkPolicyPort{p}
+}
+
+func TestPolicyEvaluator(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"team": "shop"}}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitoring", Labels: map[string]string{"team": "infra"}}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+		policyPod("web", "shop", "web", "10.128.0.5"),
+		policyPod("db", "shop", "db", "10.128.0.6"),
+		policyPod("prometheus", "monitoring", "prometheus", "10.128.1.5"),
+		policyPod("client", "other", "client", "10.128.2.5"),
+		namedPortPod("api", "shop", "10.128.0.7", "http", 8080),
+		&networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-ingress", Namespace: "shop"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+				Ingress: []networkingv1.NetworkPolicyIngressRule{{
+					From:  []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}}},
+					Ports: policyPorts(v1.ProtocolTCP, 5432, 0),
+				}},
+			},
+		},
+		&networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-ingress", Namespace: "shop"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				Ingress: []networkingv1.NetworkPolicyIngressRule{{
+					From:  []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "infra"}}}},
+					Ports: policyPorts(v1.ProtocolTCP, 8080, 8090),
+				}, {
+					From: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "192.168.0.0/16", Except: []string{"192.168.1.0/24"}}}},
+				}},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			},
+		},
+		&networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-ingress", Namespace: "shop"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+				Ingress: []networkingv1.NetworkPolicyIngressRule{{
+					Ports: []networkingv1.NetworkPolicyPort{{Port: &intstr.IntOrString{Type: intstr.String, StrVal: "http"}}},
+				}},
+			},
+		},
+		&networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "deny-egress", Namespace: "other"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			},
+		},
+	)
+	kubeData := &KubeData{stopChan: make(chan struct{})}
+	defer close(kubeData.stopChan)
+	require.NoError(t, kubeData.setConfig(nil))
+	require.NoError(t, kubeData.initInformers(client))
+
+	evaluator, err := NewPolicyEvaluator(client, kubeData)
+	require.NoError(t, err)
+	defer close(evaluator.stopChan)
+
+	for _, tc := range []struct {
+		name     string
+		src, dst string
+		port     int
+		protocol string
+		expected *PolicyResult
+	}{
+		{"pod selector", "10.128.0.5", "10.128.0.6", 5432, "TCP",
+			&PolicyResult{Verdict: PolicyAllow, Policies: []string{"shop/db-ingress"}}},
+		{"pod selector, other port", "10.128.0.5", "10.128.0.6", 3306, "TCP",
+			&PolicyResult{Verdict: PolicyDeny, Policies: []string{"shop/db-ingress"}}},
+		{"pod selector, other protocol", "10.128.0.5", "10.128.0.6", 5432, "UDP",
+			&PolicyResult{Verdict: PolicyDeny, Policies: []string{"shop/db-ingress"}}},
+		{"pod selector, other pod", "10.128.1.5", "10.128.0.6", 5432, "TCP",
+			&PolicyResult{Verdict: PolicyDeny, Policies: []string{"shop/db-ingress"}}},
+		{"namespace selector, port range", "10.128.1.5", "10.128.0.5", 8085, "TCP",
+			&PolicyResult{Verdict: PolicyAllow, Policies: []string{"shop/web-ingress"}}},
+		{"namespace selector, out of port range", "10.128.1.5", "10.128.0.5", 9000, "TCP",
+			&PolicyResult{Verdict: PolicyDeny, Policies: []string{"shop/web-ingress"}}},
+		{"ip block", "192.168.2.3", "10.128.0.5", 1234, "UDP",
+			&PolicyResult{Verdict: PolicyAllow, Policies: []string{"shop/web-ingress"}}},
+		{"ip block exception", "192.168.1.3", "10.128.0.5", 1234, "UDP",
+			&PolicyResult{Verdict: PolicyDeny, Policies: []string{"shop/web-ingress"}}},
+		{"egress isolation", "10.128.2.5", "8.8.8.8", 53, "UDP",
+			&PolicyResult{Verdict: PolicyDeny, Policies: []string{"other/deny-egress"}}},
+		{"named port", "10.128.1.5", "10.128.0.7", 8080, "TCP",
+			&PolicyResult{Verdict: PolicyAllow, Policies: []string{"shop/api-ingress"}}},
+		{"named port, other port", "10.128.1.5", "10.128.0.7", 9090, "TCP",
+			&PolicyResult{Verdict: PolicyDeny, Policies: []string{"shop/api-ingress"}}},
+		{"named port, other protocol", "10.128.1.5", "10.128.0.7", 8080, "UDP",
+			&PolicyResult{Verdict: PolicyDeny, Policies: []string{"shop/api-ingress"}}},
+		{"not isolated", "10.128.1.5", "8.8.8.8", 53, "UDP",
+			&PolicyResult{Verdict: PolicyAllow}},
+		{"no pod", "1.1.1.1", "8.8.8.8", 53, "UDP", nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, evaluator.Evaluate(tc.src, tc.dst, tc.port, tc.protocol, time.Time{}))
+		})
+	}
+}
+
+func TestCompileNetworkPolicy_Invalid(t *testing.T) {
+	_, err := compileNetworkPolicy(&networkingv1.NetworkPolicy{
+		Spec: networkingv1.NetworkPolicySpec{
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "not-a-cidr"}}},
+			}},
+		},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package location
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/ip2location/ip2location-go/v9"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/ipdb"
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// DB provides the geo-location of IP addresses from a local database file, which can be either a
+// MaxMind GeoLite2-City database (.mmdb extension) or an IP2Location BIN database (any other extension).
+type DB struct {
+	*ipdb.DB
+}
+
+// OpenDB opens the provided database file. When checksumPath is not empty, the SHA-256 checksum of the
+// database is verified against the content of that file, which follows the sha256sum output format.
+func OpenDB(path, checksumPath string) (*DB, error) {
+	db, err := ipdb.Open(path, ipdb.Options{Kind: "location", ChecksumPath: checksumPath})
+	if err != nil {
+		return nil, err
+	}
+	return &DB{DB: db}, nil
+}
+
+// GetLocation returns the geo-location of the provided IP
+func (db *DB) GetLocation(ip string) (*Info, error) {
+	r := db.RLock()
+	defer db.RUnlock()
+	if r.MaxMind != nil {
+		return lookupMaxMind(r.MaxMind, ip)
+	}
+	return lookupIP2Location(r.IP2Location, ip)
+}
+
+func lookupIP2Location(db *ip2location.DB, ip string) (*Info, error) {
+	res, err := db.Get_all(ip)
+	if err != nil {
+		return nil, err
+	}
+	return &Info{
+		CountryName:     res.Country_short,
+		CountryLongName: res.Country_long,
+		RegionName:      res.Region,
+		CityName:        res.City,
+		Latitude:        fmt.Sprintf("%f", res.Latitude),
+		Longitude:       fmt.Sprintf("%f", res.Longitude),
+	}, nil
+}
+
+type maxMindRecord struct {
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	Subdivisions []struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Location struct {
+		Latitude  float64 `maxminddb:"latitude"`
+		Longitude float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+}
+
+func lookupMaxMind(db *maxminddb.Reader, ip string) (*Info, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, fmt.Errorf("invalid IP address %q", ip)
+	}
+	var record maxMindRecord
+	_, ok, err := db.LookupNetwork(parsed, &record)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("IP address %s not found", ip)
+	}
+	info := Info{
+		CountryName:     record.Country.ISOCode,
+		CountryLongName: record.Country.Names["en"],
+		CityName:        record.City.Names["en"],
+		Latitude:        fmt.Sprintf("%f", record.Location.Latitude),
+		Longitude:       fmt.Sprintf("%f", record.Location.Longitude),
+	}
+	if len(record.Subdivisions) > 0 {
+		info.RegionName = record.Subdivisions[0].Names["en"]
+	}
+	return &info, nil
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package location
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testMaxMindDB     = "testdata/GeoLite2-City-test.mmdb"
+	testIP2LocationDB = "testdata/IP2LOCATION-DB5-test.BIN"
+)
+
+func TestOpenDB_MaxMind(t *testing.T) {
+	db, err := OpenDB(testMaxMindDB, "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	info, err := db.GetLocation("8.8.8.8")
+	require.NoError(t, err)
+	require.Equal(t, &Info{
+		CountryName:     "US",
+		CountryLongName: "United States",
+		RegionName:      "California",
+		CityName:        "Mountain View",
+		Latitude:        "37.386000",
+		Longitude:       "-122.083800",
+	}, info)
+
+	info, err = db.GetLocation("2a01:e00::1")
+	require.NoError(t, err)
+	require.Equal(t, "Paris", info.CityName)
+
+	_, err = db.GetLocation("10.0.0.1")
+	require.Error(t, err)
+	_, err = db.GetLocation("invalid")
+	require.Error(t, err)
+}
+
+func TestOpenDB_IP2Location(t *testing.T) {
+	db, err := OpenDB(testIP2LocationDB, "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	info, err := db.GetLocation("8.8.8.8")
+	require.NoError(t, err)
+	require.Equal(t, &Info{
+		CountryName:     "US",
+		CountryLongName: "United States of America",
+		RegionName:      "California",
+		CityName:        "Mountain View",
+		Latitude:        "37.405991",
+		Longitude:       "-122.078514",
+	}, info)
+}
+
+func TestOpenDB_Checksum(t *testing.T) {
+	content, err := os.ReadFile(testMaxMindDB)
+	require.NoError(t, err)
+	checksumPath := filepath.Join(t.TempDir(), "GeoLite2-City-test.mmdb.sha256")
+
+	// sha256sum output format
+	require.NoError(t, os.WriteFile(checksumPath, []byte(fmt.Sprintf("%x  GeoLite2-City-test.mmdb\n", sha256.Sum256(content))), 0600))
+	db, err := OpenDB(testMaxMindDB, checksumPath)
+	require.NoError(t, err)
+	db.Close()
+
+	// checksum mismatch
+	require.NoError(t, os.WriteFile(checksumPath, []byte(fmt.Sprintf("%x\n", sha256.Sum256([]byte("other")))), 0600))
+	_, err = OpenDB(testMaxMindDB, checksumPath)
+	require.ErrorContains(t, err, "checksum mismatch")
+
+	// invalid or missing checksum file
+	require.NoError(t, os.WriteFile(checksumPath, []byte("not a checksum"), 0600))
+	_, err = OpenDB(testMaxMindDB, checksumPath)
+	require.Error(t, err)
+	_, err = OpenDB(testMaxMindDB, checksumPath+".notfound")
+	require.Error(t, err)
+}
+
+func TestOpenDB_Errors(t *testing.T) {
+	_, err := OpenDB("testdata/notfound.mmdb", "")
+	require.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "invalid.mmdb")
+	require.NoError(t, os.WriteFile(path, []byte("invalid"), 0600))
+	_, err = OpenDB(path, "")
+	require.Error(t, err)
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "location.mmdb")
+	content, err := os.ReadFile(testMaxMindDB)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, content, 0600))
+	db, err := OpenDB(path, "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	// invalid content: previous database is kept
+	replaceFile(t, path, []byte("invalid"))
+	require.Error(t, db.Reload())
+	info, err := db.GetLocation("8.8.8.8")
+	require.NoError(t, err)
+	require.Equal(t, "Mountain View", info.CityName)
+
+	replaceFile(t, path, content)
+	require.NoError(t, db.Reload())
+	info, err = db.GetLocation("8.8.8.8")
+	require.NoError(t, err)
+	require.Equal(t, "Mountain View", info.CityName)
+}
+
+// replaceFile atomically replaces the content of the database file, as the databases are read from the file
+func replaceFile(t *testing.T, path string, content []byte) {
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, content, 0600))
+	require.NoError(t, os.Rename(tmp, path))
+}
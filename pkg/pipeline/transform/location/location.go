@@ -19,7 +19,6 @@ package location
 
 import (
 	"archive/zip"
-	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -29,7 +28,6 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/ip2location/ip2location-go/v9"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -50,7 +48,7 @@ const (
 	DbUrl = "https://raw.githubusercontent.com/netobserv/flowlogs-pipeline/main/contrib/location/location.db"
 )
 
-var locationDB *ip2location.DB
+var locationDB *DB
 
 type OSIO struct {
 	Stat     func(string) (os.FileInfo, error)
@@ -74,6 +72,8 @@ func init() {
 	locationDBMutex = &sync.Mutex{}
 }
 
+// InitLocationDB downloads the IP2Location LITE database, if not already present in DBFileLocation,
+// and loads it. Prefer OpenDB with a local database where the download is not possible or not wanted.
 func InitLocationDB() error {
 	locationDBMutex.Lock()
 	defer locationDBMutex.Unlock()
@@ -85,11 +85,7 @@ func InitLocationDB() error {
 			return fmt.Errorf("failed os.Create %v ", createErr)
 		}
 
-		tr := &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		}
-		client := &http.Client{Transport: tr}
-		resp, getErr := client.Get(_dbURL)
+		resp, getErr := http.Get(_dbURL)
 		if getErr != nil {
 			return fmt.Errorf("failed http.Get %v ", getErr)
 		}
@@ -142,7 +138,7 @@ func InitLocationDB() error {
 	}
 
 	log.Debugf("Loading location DB")
-	db, openDBErr := ip2location.OpenDB(DBFileLocation + "/" + DBFilename)
+	db, openDBErr := OpenDB(DBFileLocation+"/"+DBFilename, "")
 	if openDBErr != nil {
 		return fmt.Errorf("OpenDB err - %v ", openDBErr)
 	}
@@ -157,19 +153,8 @@ func GetLocation(ip string) (error, *Info) {
 		return fmt.Errorf("no location DB available"), nil
 	}
 
-	res, err := locationDB.Get_all(ip)
-	if err != nil {
-		return err, nil
-	}
-
-	return nil, &Info{
-		CountryName:     res.Country_short,
-		CountryLongName: res.Country_long,
-		RegionName:      res.Region,
-		CityName:        res.City,
-		Latitude:        fmt.Sprintf("%f", res.Latitude),
-		Longitude:       fmt.Sprintf("%f", res.Longitude),
-	}
+	info, err := locationDB.GetLocation(ip)
+	return err, info
 }
 
 //goland:noinspection ALL
@@ -28,6 +28,7 @@ import (
 	"testing"
 
 	"github.com/ip2location/ip2location-go/v9"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/ipdb"
 	"github.com/stretchr/testify/require"
 )
 
@@ -121,7 +122,7 @@ func Test_GetLocation(t *testing.T) {
 	require.Contains(t, err.Error(), "no location DB available")
 	require.Nil(t, info)
 
-	locationDB = &ip2location.DB{}
+	locationDB = &DB{DB: ipdb.FromReader(&ipdb.Reader{IP2Location: &ip2location.DB{}})}
 	err, info = GetLocation("test")
 	require.Contains(t, info.CountryName, "Invalid database file.")
 	require.Nil(t, err)
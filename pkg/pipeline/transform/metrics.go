@@ -0,0 +1,43 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"github.com/netobserv/flowlogs-pipeline/pkg/operational"
+)
+
+var (
+	kubeHistoryLookupsCounter = operational.DefineMetric(
+		"kubernetes_history_lookups",
+		"Number of Kubernetes IP lookups resolved from the history of deleted Pods, because the IP was reassigned since the flow",
+		operational.TypeCounter,
+		"stage",
+	)
+	threatMatchesCounter = operational.DefineMetric(
+		"threat_matches",
+		"Number of flows matching a threat intelligence list",
+		operational.TypeCounter,
+		"stage", "list",
+	)
+	ruleConditionEvaluationsCounter = operational.DefineMetric(
+		"rule_condition_evaluations",
+		"Number of evaluations of the transform rules conditions, by rule index and result (true, false or error). The rule is skipped unless the result is true",
+		operational.TypeCounter,
+		"stage", "rule", "result",
+	)
+)
@@ -0,0 +1,177 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package netdb
+
+const (
+	ProtocolICMP   = 1
+	ProtocolICMPv6 = 58
+)
+
+// etherTypes holds the names of the most common EtherTypes, from
+// https://www.iana.org/assignments/ieee-802-numbers/ieee-802-numbers.xhtml
+var etherTypes = map[int]string{
+	0x0800: "IPv4",
+	0x0806: "ARP",
+	0x0842: "WoL",
+	0x22F0: "AVTP",
+	0x22F3: "TRILL",
+	0x6003: "DECnet",
+	0x8035: "RARP",
+	0x809B: "AppleTalk",
+	0x80F3: "AARP",
+	0x8100: "802.1Q",
+	0x8137: "IPX",
+	0x86DD: "IPv6",
+	0x8808: "EthernetFlowControl",
+	0x8809: "SlowProtocols",
+	0x8847: "MPLS",
+	0x8848: "MPLS-multicast",
+	0x8863: "PPPoE-discovery",
+	0x8864: "PPPoE-session",
+	0x888E: "EAPoL",
+	0x88A8: "802.1ad",
+	0x88CC: "LLDP",
+	0x88E5: "MACsec",
+	0x88F7: "PTP",
+	0x8906: "FCoE",
+	0x8914: "FIP",
+	0x9000: "Loopback",
+}
+
+// EtherTypeName returns the name of the provided EtherType, or an empty string if it is unknown
+func EtherTypeName(etherType int) string {
+	return etherTypes[etherType]
+}
+
+// tcpFlags are ordered by bit position
+var tcpFlags = []string{"FIN", "SYN", "RST", "PSH", "ACK", "URG", "ECE", "CWR", "NS"}
+
+// TCPFlags returns the names of the flags set in the provided TCP flags bitmask
+func TCPFlags(bitmask int) []string {
+	var flags []string
+	for i, flag := range tcpFlags {
+		if bitmask&(1<<i) != 0 {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+type icmpType struct {
+	name  string
+	codes map[int]string
+}
+
+// icmpTypes from https://www.iana.org/assignments/icmp-parameters/icmp-parameters.xhtml
+var icmpTypes = map[int]icmpType{
+	0: {name: "Echo Reply"},
+	3: {name: "Destination Unreachable", codes: map[int]string{
+		0:  "Net Unreachable",
+		1:  "Host Unreachable",
+		2:  "Protocol Unreachable",
+		3:  "Port Unreachable",
+		4:  "Fragmentation Needed",
+		5:  "Source Route Failed",
+		6:  "Destination Network Unknown",
+		7:  "Destination Host Unknown",
+		8:  "Source Host Isolated",
+		9:  "Destination Network Administratively Prohibited",
+		10: "Destination Host Administratively Prohibited",
+		11: "Destination Network Unreachable for Type of Service",
+		12: "Destination Host Unreachable for Type of Service",
+		13: "Communication Administratively Prohibited",
+		14: "Host Precedence Violation",
+		15: "Precedence Cutoff in Effect",
+	}},
+	4: {name: "Source Quench"},
+	5: {name: "Redirect", codes: map[int]string{
+		0: "Redirect for Network",
+		1: "Redirect for Host",
+		2: "Redirect for Type of Service and Network",
+		3: "Redirect for Type of Service and Host",
+	}},
+	8:  {name: "Echo Request"},
+	9:  {name: "Router Advertisement"},
+	10: {name: "Router Solicitation"},
+	11: {name: "Time Exceeded", codes: map[int]string{
+		0: "Time to Live Exceeded in Transit",
+		1: "Fragment Reassembly Time Exceeded",
+	}},
+	12: {name: "Parameter Problem", codes: map[int]string{
+		0: "Pointer Indicates the Error",
+		1: "Missing a Required Option",
+		2: "Bad Length",
+	}},
+	13: {name: "Timestamp"},
+	14: {name: "Timestamp Reply"},
+	15: {name: "Information Request"},
+	16: {name: "Information Reply"},
+	17: {name: "Address Mask Request"},
+	18: {name: "Address Mask Reply"},
+	30: {name: "Traceroute"},
+	42: {name: "Extended Echo Request"},
+	43: {name: "Extended Echo Reply"},
+}
+
+// icmpv6Types from https://www.iana.org/assignments/icmpv6-parameters/icmpv6-parameters.xhtml
+var icmpv6Types = map[int]icmpType{
+	1: {name: "Destination Unreachable", codes: map[int]string{
+		0: "No Route to Destination",
+		1: "Communication with Destination Administratively Prohibited",
+		2: "Beyond Scope of Source Address",
+		3: "Address Unreachable",
+		4: "Port Unreachable",
+		5: "Source Address Failed Ingress/Egress Policy",
+		6: "Reject Route to Destination",
+	}},
+	2: {name: "Packet Too Big"},
+	3: {name: "Time Exceeded", codes: map[int]string{
+		0: "Hop Limit Exceeded in Transit",
+		1: "Fragment Reassembly Time Exceeded",
+	}},
+	4: {name: "Parameter Problem", codes: map[int]string{
+		0: "Erroneous Header Field Encountered",
+		1: "Unrecognized Next Header Type Encountered",
+		2: "Unrecognized IPv6 Option Encountered",
+	}},
+	128: {name: "Echo Request"},
+	129: {name: "Echo Reply"},
+	130: {name: "Multicast Listener Query"},
+	131: {name: "Multicast Listener Report"},
+	132: {name: "Multicast Listener Done"},
+	133: {name: "Router Solicitation"},
+	134: {name: "Router Advertisement"},
+	135: {name: "Neighbor Solicitation"},
+	136: {name: "Neighbor Advertisement"},
+	137: {name: "Redirect Message"},
+	143: {name: "Version 2 Multicast Listener Report"},
+}
+
+// ICMPNames returns the names of the provided ICMP (protocol 1) or ICMPv6 (protocol 58) type and code.
+// Empty strings are returned when they are unknown.
+func ICMPNames(protocol, icmpType, icmpCode int) (typeName, codeName string) {
+	types := icmpTypes
+	if protocol == ProtocolICMPv6 {
+		types = icmpv6Types
+	}
+	t, ok := types[icmpType]
+	if !ok {
+		return "", ""
+	}
+	return t.name, t.codes[icmpCode]
+}
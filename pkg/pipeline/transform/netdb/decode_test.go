@@ -0,0 +1,61 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package netdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEtherTypeName(t *testing.T) {
+	assert.Equal(t, "IPv4", EtherTypeName(0x0800))
+	assert.Equal(t, "IPv6", EtherTypeName(34525))
+	assert.Equal(t, "802.1Q", EtherTypeName(0x8100))
+	assert.Empty(t, EtherTypeName(0x1234))
+}
+
+func TestTCPFlags(t *testing.T) {
+	assert.Equal(t, []string{"SYN"}, TCPFlags(0x02))
+	assert.Equal(t, []string{"SYN", "ACK"}, TCPFlags(0x12))
+	assert.Equal(t, []string{"FIN", "PSH", "ACK"}, TCPFlags(0x19))
+	assert.Equal(t, []string{"ECE", "CWR", "NS"}, TCPFlags(0x1C0))
+	assert.Empty(t, TCPFlags(0))
+}
+
+func TestICMPNames(t *testing.T) {
+	typeName, codeName := ICMPNames(ProtocolICMP, 8, 0)
+	assert.Equal(t, "Echo Request", typeName)
+	assert.Empty(t, codeName)
+
+	typeName, codeName = ICMPNames(ProtocolICMP, 3, 3)
+	assert.Equal(t, "Destination Unreachable", typeName)
+	assert.Equal(t, "Port Unreachable", codeName)
+
+	typeName, codeName = ICMPNames(ProtocolICMPv6, 1, 4)
+	assert.Equal(t, "Destination Unreachable", typeName)
+	assert.Equal(t, "Port Unreachable", codeName)
+
+	typeName, codeName = ICMPNames(ProtocolICMPv6, 128, 0)
+	assert.Equal(t, "Echo Request", typeName)
+	assert.Empty(t, codeName)
+
+	// ICMPv6 types don't match ICMP ones
+	typeName, _ = ICMPNames(ProtocolICMP, 128, 0)
+	assert.Empty(t, typeName)
+}
@@ -41,12 +41,16 @@ type nameKey struct {
 }
 
 type ServiceNames struct {
-	protoNums map[int]struct{}
+	// key: protocol number, value: protocol name
+	protoNums map[int]string
 	// key: protocol name, value: protocol number
 	protoNames  map[string]int
 	byPort      map[int]string
 	byProtoNum  map[numKey]string
 	byProtoName map[nameKey]string
+	// overrides take precedence over the services database
+	overrideByPort     map[int]string
+	overrideByProtoNum map[numKey]string
 }
 
 // LoadServicesDB receives readers to the /etc/protocols and /etc/services formatted content
@@ -54,11 +58,14 @@ type ServiceNames struct {
 func LoadServicesDB(protocols, services io.Reader) (*ServiceNames, error) {
 	log := slog.WithField("method", "LoadServicesDB")
 	db := ServiceNames{
-		protoNums:   map[int]struct{}{},
+		protoNums:   map[int]string{},
 		protoNames:  map[string]int{},
 		byPort:      map[int]string{},
 		byProtoNum:  map[numKey]string{},
 		byProtoName: map[nameKey]string{},
+
+		overrideByPort:     map[int]string{},
+		overrideByProtoNum: map[numKey]string{},
 	}
 	// Load protocols
 	protoData, err := io.ReadAll(protocols)
@@ -87,7 +94,9 @@ func LoadServicesDB(protocols, services io.Reader) (*ServiceNames, error) {
 			continue
 		}
 
-		db.protoNums[int(num)] = struct{}{}
+		if _, ok := db.protoNums[int(num)]; !ok {
+			db.protoNums[int(num)] = fields[0]
+		}
 		db.protoNames[fields[0]] = int(num)
 		for _, alias := range fields[2:] {
 			db.protoNames[alias] = int(num)
@@ -102,51 +111,121 @@ func LoadServicesDB(protocols, services io.Reader) (*ServiceNames, error) {
 	}
 
 	for i, line := range strings.Split(string(svcData), "\n") {
-		line = strings.TrimSpace(line)
-		split := strings.SplitN(line, "#", 2)
-		fields := strings.Fields(split[0])
-		if len(fields) < 2 {
-			continue
-		}
-
-		svcName := fields[0]
-		portproto := strings.SplitN(fields[1], "/", 2)
-		protoName := portproto[1]
-		port, err := strconv.ParseInt(portproto[0], 10, 32)
+		svcName, port, protoName, err := parseServiceLine(line)
 		if err != nil {
 			log.WithFields(logrus.Fields{
 				logrus.ErrorKey: err,
 				"lineNum":       i,
 				"line":          line,
-			}).Debug("wrong service port number. Ignoring entry")
+			}).Debug("wrong service entry. Ignoring it")
+			continue
+		}
+		if svcName == "" {
 			continue
 		}
-		db.byPort[int(port)] = svcName
+		db.byPort[port] = svcName
 		if protoNum, ok := db.protoNames[protoName]; ok {
-			db.byProtoNum[numKey{port: int(port), protocolNumber: protoNum}] = svcName
+			db.byProtoNum[numKey{port: port, protocolNumber: protoNum}] = svcName
 		}
-		db.byProtoName[nameKey{port: int(port), protocolName: protoName}] = svcName
+		db.byProtoName[nameKey{port: port, protocolName: protoName}] = svcName
 		for _, alias := range protoAliases[protoName] {
-			db.byProtoName[nameKey{port: int(port), protocolName: alias}] = svcName
+			db.byProtoName[nameKey{port: port, protocolName: alias}] = svcName
 		}
 	}
 	return &db, nil
 }
 
+// parseServiceLine parses a /etc/services formatted line. An empty service name is returned
+// for empty or comment lines.
+func parseServiceLine(line string) (svcName string, port int, protoName string, err error) {
+	split := strings.SplitN(strings.TrimSpace(line), "#", 2)
+	fields := strings.Fields(split[0])
+	if len(fields) < 2 {
+		return "", 0, "", nil
+	}
+	portproto := strings.SplitN(fields[1], "/", 2)
+	if len(portproto) < 2 {
+		return "", 0, "", fmt.Errorf("missing protocol in %q", fields[1])
+	}
+	port64, err := strconv.ParseInt(portproto[0], 10, 32)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("wrong service port number: %w", err)
+	}
+	return fields[0], int(port64), portproto[1], nil
+}
+
+// AddOverride registers a service name that takes precedence over the services database for the
+// provided port and protocol (name, alias or number). An empty protocol matches any protocol.
+func (db *ServiceNames) AddOverride(port int, protocol, svcName string) error {
+	if protocol == "" {
+		db.overrideByPort[port] = svcName
+		return nil
+	}
+	protoNum, err := strconv.Atoi(protocol)
+	if err != nil {
+		var ok bool
+		if protoNum, ok = db.protoNames[protocol]; !ok {
+			return fmt.Errorf("unknown protocol %q for service %q", protocol, svcName)
+		}
+	}
+	db.overrideByProtoNum[numKey{port: port, protocolNumber: protoNum}] = svcName
+	return nil
+}
+
+// LoadOverrides reads /etc/services formatted content and registers its entries as overrides
+func (db *ServiceNames) LoadOverrides(services io.Reader) error {
+	svcData, err := io.ReadAll(services)
+	if err != nil {
+		return fmt.Errorf("reading services overrides: %w", err)
+	}
+	for i, line := range strings.Split(string(svcData), "\n") {
+		svcName, port, protoName, err := parseServiceLine(line)
+		if err == nil && svcName != "" {
+			err = db.AddOverride(port, protoName, svcName)
+		}
+		if err != nil {
+			return fmt.Errorf("services overrides line %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (db *ServiceNames) override(port, protoNum int) (string, bool) {
+	if svcName, ok := db.overrideByProtoNum[numKey{port: port, protocolNumber: protoNum}]; ok {
+		return svcName, true
+	}
+	svcName, ok := db.overrideByPort[port]
+	return svcName, ok
+}
+
 // ByPortAndProtocolName returns the service name given a port and a protocol name (or
-// its alias). If the protocol does not exist, returns the name of any service matching
-// the port number.
+// its alias), looking first into the overrides. If the protocol does not exist, returns
+// the name of any service matching the port number.
 func (db *ServiceNames) ByPortAndProtocolName(port int, nameOrAlias string) string {
-	if _, ok := db.protoNames[nameOrAlias]; ok {
+	if protoNum, ok := db.protoNames[nameOrAlias]; ok {
+		if svcName, ok := db.override(port, protoNum); ok {
+			return svcName
+		}
 		return db.byProtoName[nameKey{port: port, protocolName: nameOrAlias}]
 	}
+	if svcName, ok := db.overrideByPort[port]; ok {
+		return svcName
+	}
 	return db.byPort[port]
 }
 
-// ByPortAndProtocolNumber returns the service name given a port and a protocol number.
-// If the protocol does not exist, returns the name of any service matching
-// the port number.
+// ProtocolName returns the name of the protocol with the provided number, or an empty string if it is unknown
+func (db *ServiceNames) ProtocolName(protoNum int) string {
+	return db.protoNums[protoNum]
+}
+
+// ByPortAndProtocolNumber returns the service name given a port and a protocol number,
+// looking first into the overrides. If the protocol does not exist, returns the name of
+// any service matching the port number.
 func (db *ServiceNames) ByPortAndProtocolNumber(port, protoNum int) string {
+	if svcName, ok := db.override(port, protoNum); ok {
+		return svcName
+	}
 	if _, ok := db.protoNums[protoNum]; ok {
 		return db.byProtoNum[numKey{port: port, protocolNumber: protoNum}]
 	}
@@ -3,6 +3,7 @@ package netdb
 import (
 	"os"
 	"path"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,6 +52,37 @@ func TestServicesDB(t *testing.T) {
 	assert.Empty(t, db.ByPortAndProtocolName(1433, "MUX"))
 }
 
+func TestServicesDBOverrides(t *testing.T) {
+	db, err := testingServicesDB()
+	require.NoError(t, err)
+
+	require.NoError(t, db.LoadOverrides(strings.NewReader(`
+# internal services
+kafka           9092/tcp
+https-internal  8443/tcp
+`)))
+	require.NoError(t, db.AddOverride(1433, "", "internal-sql"))
+	require.NoError(t, db.AddOverride(138, "17", "internal-dgm"))
+	require.Error(t, db.AddOverride(1234, "tralara", "unknown-protocol"))
+	require.Error(t, db.LoadOverrides(strings.NewReader("missing-protocol 1234")))
+
+	// overrides by port and protocol
+	assert.Equal(t, "kafka", db.ByPortAndProtocolNumber(9092, 6))
+	assert.Equal(t, "kafka", db.ByPortAndProtocolName(9092, "tcp"))
+	assert.Equal(t, "kafka", db.ByPortAndProtocolName(9092, "TCP"))
+	// other protocols fall back to the services database
+	assert.Equal(t, "XmlIpcRegSvc", db.ByPortAndProtocolNumber(9092, 17))
+	assert.Equal(t, "https-internal", db.ByPortAndProtocolName(8443, "tcp"))
+	assert.Equal(t, "internal-dgm", db.ByPortAndProtocolName(138, "udp"))
+	assert.Equal(t, "netbios-dgm", db.ByPortAndProtocolName(138, "tcp"))
+
+	// overrides for any protocol
+	assert.Equal(t, "internal-sql", db.ByPortAndProtocolNumber(1433, 6))
+	assert.Equal(t, "internal-sql", db.ByPortAndProtocolName(1433, "udp"))
+	assert.Equal(t, "internal-sql", db.ByPortAndProtocolName(1433, "tralara"))
+	assert.Equal(t, "internal-sql", db.ByPortAndProtocolName(1433, "mux"))
+}
+
 func BenchmarkGetProtoByNumber(b *testing.B) {
 	b.StopTimer()
 	db, err := testingServicesDB()
@@ -80,3 +112,14 @@ func BenchmarkGetProtoByName(b *testing.B) {
 		db.ByPortAndProtocolName(27017, "TCP")
 	}
 }
+
+func TestProtocolName(t *testing.T) {
+	db, err := testingServicesDB()
+	require.NoError(t, err)
+
+	assert.Equal(t, "tcp", db.ProtocolName(6))
+	assert.Equal(t, "icmp", db.ProtocolName(1))
+	assert.Equal(t, "ipv6-icmp", db.ProtocolName(58))
+	assert.Equal(t, "ip", db.ProtocolName(0))
+	assert.Empty(t, db.ProtocolName(9999))
+}
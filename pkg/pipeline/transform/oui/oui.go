@@ -0,0 +1,168 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package oui
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// lengths in bits of the MA-S, MA-M and MA-L assignments
+const (
+	maSBits = 36
+	maMBits = 28
+	maLBits = 24
+)
+
+// prefixLengths are looked up from the longest
+var prefixLengths = []int{maSBits, maMBits, maLBits}
+
+const (
+	multicastBit           = 0x01
+	locallyAdministeredBit = 0x02
+)
+
+// DB provides the vendor of MAC addresses from IEEE registry files in CSV format (MA-L, MA-M and MA-S,
+// as published at https://standards-oui.ieee.org), using the longest matching assignment.
+type DB struct {
+	paths []string
+	// mutex prevents reading the registry while it is replaced during reloads
+	mutex    sync.RWMutex
+	registry registry
+}
+
+// registry holds, for each assignment length in bits, the organization names by prefix
+type registry map[int]map[uint64]string
+
+// OpenDB loads the provided registry files
+func OpenDB(paths []string) (*DB, error) {
+	r, err := loadRegistry(paths)
+	if err != nil {
+		return nil, err
+	}
+	return &DB{paths: paths, registry: r}, nil
+}
+
+// Reload reloads the registry files. The previous content is kept in case of error.
+func (db *DB) Reload() error {
+	r, err := loadRegistry(db.paths)
+	if err != nil {
+		return err
+	}
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	db.registry = r
+	return nil
+}
+
+// Lookup returns the organization the MAC address is assigned to. Locally administered addresses are not
+// assigned by the IEEE, so they are never found. The multicast bit is ignored, since multicast addresses
+// are allocated within the range of their owner (e.g. 01:00:5E for IANA).
+func (db *DB) Lookup(mac net.HardwareAddr) (string, bool) {
+	if len(mac) < 6 || IsLocallyAdministered(mac) {
+		return "", false
+	}
+	// the 40 first bits cover the longest assignment
+	var bits uint64
+	for _, b := range mac[:5] {
+		bits = bits<<8 | uint64(b)
+	}
+	bits &^= multicastBit << 32
+	db.mutex.RLock()
+	defer db.mutex.RUnlock()
+	for _, length := range prefixLengths {
+		if vendor, ok := db.registry[length][bits>>(40-length)]; ok {
+			return vendor, true
+		}
+	}
+	return "", false
+}
+
+// IsLocallyAdministered returns whether the MAC address is locally administered rather than universally
+// administered (i.e. assigned by the IEEE), such as randomized or virtual interfaces addresses
+func IsLocallyAdministered(mac net.HardwareAddr) bool {
+	return len(mac) > 0 && mac[0]&locallyAdministeredBit != 0
+}
+
+// IsMulticast returns whether the MAC address is a group (multicast or broadcast) address
+func IsMulticast(mac net.HardwareAddr) bool {
+	return len(mac) > 0 && mac[0]&multicastBit != 0
+}
+
+func loadRegistry(paths []string) (registry, error) {
+	r := registry{}
+	for _, length := range prefixLengths {
+		r[length] = map[uint64]string{}
+	}
+	for _, path := range paths {
+		if err := r.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r registry) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening OUI registry %q: %w", path, err)
+	}
+	defer f.Close()
+	reader := csv.NewReader(f)
+	// the organization address is sometimes missing
+	reader.FieldsPerRecord = -1
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading OUI registry %q: %w", path, err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) < 3 {
+			return fmt.Errorf("invalid OUI registry %q, line %d: expected at least 3 columns, got %d", path, line, len(record))
+		}
+		registryName := strings.TrimSpace(record[0])
+		switch registryName {
+		case "Registry":
+			// header
+			continue
+		case "CID":
+			// Company IDs are locally administered, so they can't identify the vendor of an address
+			continue
+		}
+		assignment := strings.TrimSpace(record[1])
+		length := len(assignment) * 4
+		if _, ok := r[length]; !ok {
+			return fmt.Errorf("invalid OUI registry %q, line %d: unexpected %s assignment length %q", path, line, registryName, assignment)
+		}
+		prefix, err := strconv.ParseUint(assignment, 16, 64)
+		if err != nil {
+			return fmt.Errorf("invalid OUI registry %q, line %d: %w", path, line, err)
+		}
+		r[length][prefix] = strings.TrimSpace(record[2])
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package oui
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var registryFiles = []string{
+	filepath.Join("testdata", "oui.csv"),
+	filepath.Join("testdata", "mam.csv"),
+	filepath.Join("testdata", "oui36.csv"),
+}
+
+func lookup(t *testing.T, db *DB, mac string) (string, bool) {
+	hw, err := net.ParseMAC(mac)
+	require.NoError(t, err)
+	return db.Lookup(hw)
+}
+
+func TestLookup(t *testing.T) {
+	db, err := OpenDB(registryFiles)
+	require.NoError(t, err)
+
+	vendor, ok := lookup(t, db, "00:00:0C:12:34:56")
+	require.True(t, ok)
+	require.Equal(t, "Cisco Systems, Inc", vendor)
+
+	vendor, ok = lookup(t, db, "3c-22-fb-aa-bb-cc")
+	require.True(t, ok)
+	require.Equal(t, "Apple, Inc.", vendor)
+
+	// longest prefix: MA-M and MA-S assignments within IEEE blocks
+	vendor, ok = lookup(t, db, "F0:AC:D7:C1:23:45")
+	require.True(t, ok)
+	require.Equal(t, "Guilin glsun Science and Tech Co.，LTD", vendor)
+	vendor, ok = lookup(t, db, "F0:AC:D7:B1:23:45")
+	require.True(t, ok)
+	require.Equal(t, "IEEE Registration Authority", vendor)
+	vendor, ok = lookup(t, db, "70:B3:D5:12:3F:FF")
+	require.True(t, ok)
+	require.Equal(t, "Amfitech ApS", vendor)
+	vendor, ok = lookup(t, db, "70:B3:D5:12:4F:FF")
+	require.True(t, ok)
+	require.Equal(t, "IEEE Registration Authority", vendor)
+
+	// multicast addresses belong to the range of their owner
+	vendor, ok = lookup(t, db, "01:00:5E:00:00:FB")
+	require.True(t, ok)
+	require.Equal(t, "ICANN IANA Department", vendor)
+
+	// locally administered addresses aren't assigned by the IEEE, even with a known prefix
+	_, ok = lookup(t, db, "3E:22:FB:AA:BB:CC")
+	require.False(t, ok)
+	// Company IDs are ignored
+	_, ok = lookup(t, db, "0A:1B:2C:00:00:01")
+	require.False(t, ok)
+	_, ok = lookup(t, db, "AC:DE:48:00:11:22")
+	require.False(t, ok)
+}
+
+func TestFlags(t *testing.T) {
+	mac, err := net.ParseMAC("3E:22:FB:AA:BB:CC")
+	require.NoError(t, err)
+	require.True(t, IsLocallyAdministered(mac))
+	require.False(t, IsMulticast(mac))
+
+	mac, err = net.ParseMAC("01:00:5E:00:00:FB")
+	require.NoError(t, err)
+	require.False(t, IsLocallyAdministered(mac))
+	require.True(t, IsMulticast(mac))
+
+	mac, err = net.ParseMAC("FF:FF:FF:FF:FF:FF")
+	require.NoError(t, err)
+	require.True(t, IsLocallyAdministered(mac))
+	require.True(t, IsMulticast(mac))
+}
+
+func TestInvalidRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "oui.csv")
+	require.NoError(t, os.WriteFile(path, []byte("Registry,Assignment,Organization Name,Organization Address\nMA-L,00000C1234,Too long,\n"), 0600))
+	_, err := OpenDB([]string{path})
+	require.ErrorContains(t, err, "line 2")
+
+	require.NoError(t, os.WriteFile(path, []byte("MA-L,ZZ000C,Not hex,\n"), 0600))
+	_, err = OpenDB([]string{path})
+	require.Error(t, err)
+
+	_, err = OpenDB([]string{filepath.Join("testdata", "missing.csv")})
+	require.Error(t, err)
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "oui.csv")
+	require.NoError(t, os.WriteFile(path, []byte("MA-L,00000C,Old vendor,\n"), 0600))
+	db, err := OpenDB([]string{path})
+	require.NoError(t, err)
+	vendor, _ := lookup(t, db, "00:00:0C:00:00:01")
+	require.Equal(t, "Old vendor", vendor)
+
+	require.NoError(t, os.WriteFile(path, []byte("MA-L,00000C,New vendor,\n"), 0600))
+	require.NoError(t, db.Reload())
+	vendor, _ = lookup(t, db, "00:00:0C:00:00:01")
+	require.Equal(t, "New vendor", vendor)
+
+	// invalid content keeps the previous registry
+	require.NoError(t, os.WriteFile(path, []byte("MA-L,00000C\n"), 0600))
+	require.Error(t, db.Reload())
+	vendor, _ = lookup(t, db, "00:00:0C:00:00:01")
+	require.Equal(t, "New vendor", vendor)
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package rdns
+
+import (
+	"github.com/netobserv/flowlogs-pipeline/pkg/operational"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	cacheRequestsCounter = operational.DefineMetric(
+		"reverse_dns_cache_requests",
+		"Number of reverse DNS cache requests, per result (hit or miss)",
+		operational.TypeCounter,
+		"stage", "result",
+	)
+	droppedLookupsCounter = operational.DefineMetric(
+		"reverse_dns_dropped_lookups",
+		"Number of reverse DNS lookups not performed because of a full queue",
+		operational.TypeCounter,
+		"stage",
+	)
+	lookupLatencyHistogram = operational.DefineMetric(
+		"reverse_dns_lookup_duration_ms",
+		"Reverse DNS lookup duration, in milliseconds",
+		operational.TypeHistogram,
+		"stage",
+	)
+)
+
+type metrics struct {
+	hits           prometheus.Counter
+	misses         prometheus.Counter
+	droppedLookups prometheus.Counter
+	lookupLatency  prometheus.Histogram
+}
+
+func newMetrics(opMetrics *operational.Metrics, stage string) *metrics {
+	requests := opMetrics.NewCounterVec(&cacheRequestsCounter)
+	return &metrics{
+		hits:           requests.WithLabelValues(stage, "hit"),
+		misses:         requests.WithLabelValues(stage, "miss"),
+		droppedLookups: opMetrics.NewCounter(&droppedLookupsCounter, stage),
+		lookupLatency:  opMetrics.NewHistogram(&lookupLatencyHistogram, []float64{1, 5, 10, 50, 100, 500, 1000, 5000}, stage),
+	}
+}
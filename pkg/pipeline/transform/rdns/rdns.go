@@ -0,0 +1,165 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package rdns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/operational"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/utils"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("component", "rdns.Resolver")
+
+type lookupFunc func(ctx context.Context, ip string) ([]string, error)
+
+// Resolver resolves IPs into host names without ever blocking its callers: resolutions are
+// performed in background by a bounded number of workers, and their results (successful or not)
+// are cached for a configurable time.
+type Resolver struct {
+	lookup      lookupFunc
+	cache       *utils.TimedCache
+	timeout     time.Duration
+	positiveTTL time.Duration
+	negativeTTL time.Duration
+	queue       chan string
+	// pending avoids queueing several lookups for the same IP
+	pendingMu sync.Mutex
+	pending   map[string]struct{}
+	metrics   *metrics
+}
+
+// cacheEntry is immutable once stored in the cache. An empty host name means that the resolution failed
+type cacheEntry struct {
+	hostName string
+	expiry   time.Time
+}
+
+// NewResolver creates a Resolver and starts its workers. It fails when the configured resolver address isn't a
+// valid host:port address, since every lookup would fail otherwise.
+func NewResolver(opMetrics *operational.Metrics, stage string, cfg *api.NetworkTransformReverseDNS) (*Resolver, error) {
+	var resolver *net.Resolver
+	if cfg == nil || cfg.ResolverAddress == "" {
+		resolver = net.DefaultResolver
+	} else {
+		if _, _, err := net.SplitHostPort(cfg.ResolverAddress); err != nil {
+			return nil, fmt.Errorf("invalid reverse DNS resolver address %q, expected host:port: %w", cfg.ResolverAddress, err)
+		}
+		dialer := net.Dialer{}
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, cfg.ResolverAddress)
+			},
+		}
+	}
+	r := newResolver(resolver.LookupAddr, newMetrics(opMetrics, stage), cfg)
+	for i := 0; i < cfg.GetMaxConcurrency(); i++ {
+		go r.worker()
+	}
+	return r, nil
+}
+
+func newResolver(lookup lookupFunc, m *metrics, cfg *api.NetworkTransformReverseDNS) *Resolver {
+	maxTTL := cfg.GetPositiveTTL()
+	if cfg.GetNegativeTTL() > maxTTL {
+		maxTTL = cfg.GetNegativeTTL()
+	}
+	return &Resolver{
+		lookup:      lookup,
+		cache:       utils.NewQuietExpiringTimedCache(maxTTL),
+		timeout:     cfg.GetTimeout(),
+		positiveTTL: cfg.GetPositiveTTL(),
+		negativeTTL: cfg.GetNegativeTTL(),
+		queue:       make(chan string, cfg.GetQueueLength()),
+		pending:     map[string]struct{}{},
+		metrics:     m,
+	}
+}
+
+// HostName returns the host name of the provided IP, if it has already been resolved.
+// Otherwise, the IP is queued for background resolution and false is returned.
+func (r *Resolver) HostName(ip string) (string, bool) {
+	if e, ok := r.cache.GetCacheEntry(ip); ok {
+		entry := e.(*cacheEntry)
+		if time.Now().Before(entry.expiry) {
+			r.metrics.hits.Inc()
+			return entry.hostName, entry.hostName != ""
+		}
+	}
+	r.metrics.misses.Inc()
+	r.enqueue(ip)
+	return "", false
+}
+
+func (r *Resolver) enqueue(ip string) {
+	r.pendingMu.Lock()
+	defer r.pendingMu.Unlock()
+	if _, ok := r.pending[ip]; ok {
+		return
+	}
+	select {
+	case r.queue <- ip:
+		r.pending[ip] = struct{}{}
+	default:
+		r.metrics.droppedLookups.Inc()
+	}
+}
+
+func (r *Resolver) worker() {
+	for {
+		select {
+		case <-utils.ExitChannel():
+			return
+		case ip := <-r.queue:
+			r.resolve(ip)
+		}
+	}
+}
+
+func (r *Resolver) resolve(ip string) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	start := time.Now()
+	names, err := r.lookup(ctx, ip)
+	r.metrics.lookupLatency.Observe(float64(time.Since(start).Milliseconds()))
+
+	entry := cacheEntry{expiry: time.Now().Add(r.negativeTTL)}
+	if err != nil {
+		log.WithError(err).WithField("ip", ip).Trace("can't resolve IP")
+	} else if len(names) > 0 {
+		entry = cacheEntry{
+			hostName: strings.TrimSuffix(names[0], "."),
+			expiry:   time.Now().Add(r.positiveTTL),
+		}
+	}
+	// the cache only refreshes the timestamp of existing entries, so we need to remove it first
+	r.cache.RemoveCacheEntry(ip)
+	r.cache.UpdateCacheEntry(ip, &entry)
+
+	r.pendingMu.Lock()
+	delete(r.pending, ip)
+	r.pendingMu.Unlock()
+}
@@ -0,0 +1,206 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package rdns
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/netobserv/flowlogs-pipeline/pkg/operational"
+	"github.com/netobserv/flowlogs-pipeline/pkg/test"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const timeout = 5 * time.Second
+
+func newTestOpMetrics() *operational.Metrics {
+	test.ResetPromRegistry()
+	return operational.NewMetrics(&config.MetricsSettings{})
+}
+
+func TestResolver_Cache(t *testing.T) {
+	lookups := 0
+	m := newMetrics(newTestOpMetrics(), "test")
+	r := newResolver(func(_ context.Context, ip string) ([]string, error) {
+		lookups++
+		if ip == "1.2.3.4" {
+			return []string{"host.example.com."}, nil
+		}
+		return nil, errors.New("not found")
+	}, m, &api.NetworkTransformReverseDNS{
+		PositiveTTL: api.Duration{Duration: time.Hour},
+		NegativeTTL: api.Duration{Duration: time.Hour},
+	})
+
+	// cache miss: not blocking, just queueing the lookup
+	_, ok := r.HostName("1.2.3.4")
+	require.False(t, ok)
+	_, ok = r.HostName("4.3.2.1")
+	require.False(t, ok)
+	// same IP is not queued twice
+	_, ok = r.HostName("1.2.3.4")
+	require.False(t, ok)
+	require.Len(t, r.queue, 2)
+
+	r.resolve(<-r.queue)
+	r.resolve(<-r.queue)
+	require.Equal(t, 2, lookups)
+
+	// positive cache
+	name, ok := r.HostName("1.2.3.4")
+	require.True(t, ok)
+	require.Equal(t, "host.example.com", name)
+
+	// negative cache
+	_, ok = r.HostName("4.3.2.1")
+	require.False(t, ok)
+	require.Empty(t, r.queue)
+}
+
+func TestResolver_Expiry(t *testing.T) {
+	m := newMetrics(newTestOpMetrics(), "test")
+	names := []string{"first.example.com"}
+	r := newResolver(func(_ context.Context, ip string) ([]string, error) {
+		return names, nil
+	}, m, &api.NetworkTransformReverseDNS{
+		PositiveTTL: api.Duration{Duration: time.Nanosecond},
+	})
+
+	_, ok := r.HostName("1.2.3.4")
+	require.False(t, ok)
+	r.resolve(<-r.queue)
+
+	// entry expired: it is resolved again
+	_, ok = r.HostName("1.2.3.4")
+	require.False(t, ok)
+	names = []string{"second.example.com"}
+	r.positiveTTL = time.Hour
+	r.resolve(<-r.queue)
+
+	name, ok := r.HostName("1.2.3.4")
+	require.True(t, ok)
+	require.Equal(t, "second.example.com", name)
+}
+
+func TestResolver_QueueFull(t *testing.T) {
+	m := newMetrics(newTestOpMetrics(), "test")
+	r := newResolver(func(_ context.Context, ip string) ([]string, error) {
+		return []string{"host"}, nil
+	}, m, &api.NetworkTransformReverseDNS{QueueLength: 1})
+
+	_, ok := r.HostName("1.1.1.1")
+	require.False(t, ok)
+	_, ok = r.HostName("2.2.2.2")
+	require.False(t, ok)
+	require.Len(t, r.queue, 1)
+
+	// the dropped IP can be queued again later
+	r.resolve(<-r.queue)
+	_, ok = r.HostName("2.2.2.2")
+	require.False(t, ok)
+	require.Equal(t, "2.2.2.2", <-r.queue)
+}
+
+func TestResolver_DNSServer(t *testing.T) {
+	addr := startFakeDNS(t, map[string]string{
+		"3.2.1.10.in-addr.arpa.": "pod.example.com.",
+	})
+	r, err := NewResolver(newTestOpMetrics(), "test", &api.NetworkTransformReverseDNS{
+		ResolverAddress: addr,
+	})
+	require.NoError(t, err)
+
+	requireEventually(t, func() bool {
+		name, ok := r.HostName("10.1.2.3")
+		return ok && name == "pod.example.com"
+	})
+
+	// resolution failures are negatively cached
+	_, ok := r.HostName("10.3.2.1")
+	require.False(t, ok)
+	requireEventually(t, func() bool {
+		e, ok := r.cache.GetCacheEntry("10.3.2.1")
+		return ok && e.(*cacheEntry).hostName == ""
+	})
+}
+
+func TestResolver_InvalidAddress(t *testing.T) {
+	for _, addr := range []string{"10.0.0.10", "dns.example.com", "[::1]"} {
+		_, err := NewResolver(newTestOpMetrics(), "test", &api.NetworkTransformReverseDNS{
+			ResolverAddress: addr,
+		})
+		require.Error(t, err, addr)
+	}
+}
+
+func requireEventually(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			require.Fail(t, "timeout while waiting for condition")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// startFakeDNS starts an in-process UDP DNS server that answers PTR queries from the provided
+// records, and returns NXDOMAIN for any other query
+func startFakeDNS(t *testing.T, ptrRecords map[string]string) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, clientAddr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var query dnsmessage.Message
+			if err := query.Unpack(buf[:n]); err != nil || len(query.Questions) == 0 {
+				continue
+			}
+			question := query.Questions[0]
+			response := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: query.ID, Response: true, RecursionAvailable: true},
+				Questions: query.Questions,
+			}
+			if target, ok := ptrRecords[question.Name.String()]; ok && question.Type == dnsmessage.TypePTR {
+				response.Answers = []dnsmessage.Resource{{
+					Header: dnsmessage.ResourceHeader{Name: question.Name, Type: dnsmessage.TypePTR, Class: dnsmessage.ClassINET, TTL: 60},
+					Body:   &dnsmessage.PTRResource{PTR: dnsmessage.MustNewName(target)},
+				}}
+			} else {
+				response.RCode = dnsmessage.RCodeNameError
+			}
+			packed, err := response.Pack()
+			if err != nil {
+				continue
+			}
+			_, _ = conn.WriteTo(packed, clientAddr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package threat
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// stixComparison matches the comparison expressions of STIX patterns on IPs and domains, e.g.
+// ipv4-addr:value = '198.51.100.1' or ipv4-addr:value ISSUBSET '198.51.100.0/24'
+var stixComparison = regexp.MustCompile(`(ipv4-addr|ipv6-addr|domain-name):value\s*(?:=|ISSUBSET)\s*'((?:[^'\\]|\\.)*)'`)
+
+// stixQuoted matches the string literals of STIX patterns
+var stixQuoted = regexp.MustCompile(`'(?:[^'\\]|\\.)*'`)
+
+type stixBundle struct {
+	Type    string       `json:"type"`
+	Objects []stixObject `json:"objects"`
+}
+
+type stixObject struct {
+	Type           string     `json:"type"`
+	Pattern        string     `json:"pattern"`
+	PatternType    string     `json:"pattern_type"`
+	IndicatorTypes []string   `json:"indicator_types"`
+	Labels         []string   `json:"labels"`
+	Revoked        bool       `json:"revoked"`
+	ValidUntil     *time.Time `json:"valid_until"`
+}
+
+// readSTIX reads the indicators of a STIX 2.1 bundle. Only the patterns made of IP, CIDR and domain comparisons
+// combined with OR are supported, other indicators are ignored, as well as revoked and expired ones.
+func (in *indicators) readSTIX(r io.Reader, category string) error {
+	var bundle stixBundle
+	if err := json.NewDecoder(r).Decode(&bundle); err != nil {
+		return err
+	}
+	if bundle.Type != "bundle" {
+		return fmt.Errorf("expected a STIX bundle, got type %q", bundle.Type)
+	}
+	now := time.Now()
+	for i := range bundle.Objects {
+		obj := &bundle.Objects[i]
+		if obj.Type != "indicator" || obj.Revoked || (obj.ValidUntil != nil && obj.ValidUntil.Before(now)) {
+			continue
+		}
+		if obj.PatternType != "" && obj.PatternType != "stix" {
+			continue
+		}
+		if operators := stixQuoted.ReplaceAllString(obj.Pattern, "''"); strings.Contains(operators, " AND ") ||
+			strings.Contains(operators, "FOLLOWEDBY") || strings.Contains(operators, "NOT ") {
+			continue
+		}
+		objCategory := category
+		if len(obj.IndicatorTypes) > 0 {
+			objCategory = obj.IndicatorTypes[0]
+		} else if len(obj.Labels) > 0 {
+			objCategory = obj.Labels[0]
+		}
+		for _, comparison := range stixComparison.FindAllStringSubmatch(obj.Pattern, -1) {
+			value := strings.NewReplacer(`\'`, `'`, `\\`, `\`).Replace(comparison[2])
+			// invalid values are ignored rather than failing the whole bundle, which often comes from a third party
+			in.add(value, objCategory)
+		}
+	}
+	return nil
+}
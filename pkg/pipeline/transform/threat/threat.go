@@ -0,0 +1,240 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package threat
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/utils"
+)
+
+// List is a blocklist of IPs, CIDRs and domains loaded from a file. The indicators are mapped to their category,
+// which can be empty.
+type List struct {
+	config api.NetworkTransformThreatList
+	// mutex prevents reading the indicators while they are replaced during reloads
+	mutex      sync.RWMutex
+	indicators *indicators
+}
+
+type indicators struct {
+	// ips holds the category of the IPs and CIDRs
+	ips *utils.PrefixTree
+	// domains holds the category of the domains, in lower case and without trailing dot
+	domains map[string]string
+}
+
+func newIndicators() *indicators {
+	return &indicators{ips: utils.NewPrefixTree(), domains: map[string]string{}}
+}
+
+// add stores an IP, CIDR or domain indicator, returning false if it is none of them
+func (in *indicators) add(indicator, category string) bool {
+	indicator = strings.TrimSpace(indicator)
+	if strings.Contains(indicator, "/") {
+		_, cidr, err := net.ParseCIDR(indicator)
+		if err != nil {
+			return false
+		}
+		in.ips.InsertCIDR(cidr, category)
+		return true
+	}
+	if ip := net.ParseIP(indicator); ip != nil {
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			bits = 8 * net.IPv4len
+		}
+		in.ips.InsertCIDR(&net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, category)
+		return true
+	}
+	domain := normalizeDomain(indicator)
+	if !isDomain(domain) {
+		return false
+	}
+	in.domains[domain] = category
+	return true
+}
+
+func normalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+// isDomain loosely checks the domain syntax, mostly to tell domains apart from headers and malformed IPs
+func isDomain(domain string) bool {
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.Contains(domain, "..") {
+		return false
+	}
+	for _, c := range domain {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '.' || c == '_') {
+			return false
+		}
+	}
+	// IPs and CIDRs were already tried
+	return strings.IndexFunc(domain, func(c rune) bool { return (c < '0' || c > '9') && c != '.' }) >= 0
+}
+
+// OpenList loads the list file
+func OpenList(config *api.NetworkTransformThreatList) (*List, error) {
+	l := &List{config: *config}
+	in, err := l.load()
+	if err != nil {
+		return nil, err
+	}
+	l.indicators = in
+	return l, nil
+}
+
+// Name returns the name of the list
+func (l *List) Name() string {
+	return l.config.Name
+}
+
+// Reload reloads the list file. The previous indicators are kept in case of error.
+func (l *List) Reload() error {
+	in, err := l.load()
+	if err != nil {
+		return err
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.indicators = in
+	return nil
+}
+
+// Size returns the number of IP, CIDR and domain indicators
+func (l *List) Size() int {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.indicators.ips.Len() + len(l.indicators.domains)
+}
+
+// LookupIP returns the category of the most specific indicator containing the IP
+func (l *List) LookupIP(ip net.IP) (string, bool) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	category, ok := l.indicators.ips.LookupIP(ip)
+	if !ok {
+		return "", false
+	}
+	return category.(string), true
+}
+
+// LookupDomain returns the category of the domain, or of its closest listed parent domain
+func (l *List) LookupDomain(domain string) (string, bool) {
+	domain = normalizeDomain(domain)
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	for domain != "" {
+		if category, ok := l.indicators.domains[domain]; ok {
+			return category, true
+		}
+		dot := strings.IndexByte(domain, '.')
+		if dot < 0 {
+			break
+		}
+		domain = domain[dot+1:]
+	}
+	return "", false
+}
+
+func (l *List) load() (*indicators, error) {
+	f, err := os.Open(l.config.File)
+	if err != nil {
+		return nil, fmt.Errorf("opening threat list %q: %w", l.config.File, err)
+	}
+	defer f.Close()
+	in := newIndicators()
+	switch format := l.config.GetFormat(); format {
+	case api.ThreatListFormatList:
+		err = in.readList(f, l.config.Category)
+	case api.ThreatListFormatCSV:
+		err = in.readCSV(f, l.config.Category)
+	case api.ThreatListFormatSTIX:
+		err = in.readSTIX(f, l.config.Category)
+	default:
+		err = fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading threat list %q: %w", l.config.File, err)
+	}
+	return in, nil
+}
+
+// readList reads one indicator per line. Comments, starting with '#' or ';', are ignored, as well as anything
+// following the indicator, such as the references in Spamhaus DROP lists.
+func (in *indicators) readList(r io.Reader, category string) error {
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if i := strings.IndexAny(text, "#;"); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if !in.add(fields[0], category) {
+			return fmt.Errorf("line %d: invalid indicator %q", line, fields[0])
+		}
+	}
+	return scanner.Err()
+}
+
+// readCSV reads indicator,category records, the category being optional. Lines starting with '#' are ignored,
+// as well as a first header line.
+func (in *indicators) readCSV(r io.Reader, category string) error {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	first := true
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		recordCategory := category
+		if len(record) > 1 && strings.TrimSpace(record[1]) != "" {
+			recordCategory = strings.TrimSpace(record[1])
+		}
+		if !in.add(record[0], recordCategory) {
+			if first {
+				// header
+				first = false
+				continue
+			}
+			line, _ := reader.FieldPos(0)
+			return fmt.Errorf("line %d: invalid indicator %q", line, record[0])
+		}
+		first = false
+	}
+}
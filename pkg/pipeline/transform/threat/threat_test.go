@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package threat
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+func open(t *testing.T, file, category string) *List {
+	l, err := OpenList(&api.NetworkTransformThreatList{Name: file, File: filepath.Join("testdata", file), Category: category})
+	require.NoError(t, err)
+	return l
+}
+
+func requireIP(t *testing.T, l *List, ip string, category string) {
+	t.Helper()
+	found, ok := l.LookupIP(net.ParseIP(ip))
+	require.True(t, ok, ip)
+	require.Equal(t, category, found, ip)
+}
+
+func requireNoIP(t *testing.T, l *List, ip string) {
+	t.Helper()
+	_, ok := l.LookupIP(net.ParseIP(ip))
+	require.False(t, ok, ip)
+}
+
+func TestPlainList(t *testing.T) {
+	l := open(t, "drop.txt", "drop")
+	require.Equal(t, 4, l.Size())
+	requireIP(t, l, "203.0.113.54", "drop")
+	requireIP(t, l, "198.51.100.7", "drop")
+	requireIP(t, l, "2001:db8:bad::1", "drop")
+	requireNoIP(t, l, "198.51.100.8")
+	requireNoIP(t, l, "2001:db8::1")
+
+	category, ok := l.LookupDomain("cdn.Malware.example.")
+	require.True(t, ok)
+	require.Equal(t, "drop", category)
+	_, ok = l.LookupDomain("example")
+	require.False(t, ok)
+}
+
+func TestCSV(t *testing.T) {
+	l := open(t, "blocklist.csv", "default")
+	require.Equal(t, 4, l.Size())
+	// most specific indicator
+	requireIP(t, l, "192.0.2.10", "botnet")
+	requireIP(t, l, "192.0.2.11", "scanner")
+	requireNoIP(t, l, "192.0.2.16")
+	requireIP(t, l, "198.51.100.200", "default")
+	category, ok := l.LookupDomain("phishing.example")
+	require.True(t, ok)
+	require.Equal(t, "phishing", category)
+}
+
+func TestSTIX(t *testing.T) {
+	l := open(t, "indicators.json", "")
+	requireIP(t, l, "198.51.100.1", "malicious-activity")
+	requireIP(t, l, "198.51.100.130", "malicious-activity")
+	requireIP(t, l, "2001:db8::dead", "anonymization")
+	category, ok := l.LookupDomain("tor-exit.example")
+	require.True(t, ok)
+	require.Equal(t, "anonymization", category)
+	// AND patterns, expired, revoked and non-STIX indicators are ignored
+	requireNoIP(t, l, "198.51.100.2")
+	requireNoIP(t, l, "198.51.100.3")
+	requireNoIP(t, l, "198.51.100.4")
+	requireNoIP(t, l, "198.51.100.5")
+	require.Equal(t, 4, l.Size())
+}
+
+func TestInvalidList(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "list.txt")
+	require.NoError(t, os.WriteFile(path, []byte("10.0.0.1\n10.0.0.999\n"), 0600))
+	_, err := OpenList(&api.NetworkTransformThreatList{Name: "invalid", File: path})
+	require.ErrorContains(t, err, "line 2")
+
+	path = filepath.Join(dir, "list.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"type": "indicator"}`), 0600))
+	_, err = OpenList(&api.NetworkTransformThreatList{Name: "invalid", File: path})
+	require.ErrorContains(t, err, "expected a STIX bundle")
+
+	_, err = OpenList(&api.NetworkTransformThreatList{Name: "missing", File: filepath.Join(dir, "missing.txt")})
+	require.Error(t, err)
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "list.txt")
+	require.NoError(t, os.WriteFile(path, []byte("10.0.0.1\n"), 0600))
+	l, err := OpenList(&api.NetworkTransformThreatList{Name: "reloaded", File: path, Format: api.ThreatListFormatList})
+	require.NoError(t, err)
+	requireIP(t, l, "10.0.0.1", "")
+
+	require.NoError(t, os.WriteFile(path, []byte("10.0.0.2\n"), 0600))
+	require.NoError(t, l.Reload())
+	requireNoIP(t, l, "10.0.0.1")
+	requireIP(t, l, "10.0.0.2", "")
+
+	// invalid content keeps the previous indicators
+	require.NoError(t, os.WriteFile(path, []byte("not an indicator\n"), 0600))
+	require.Error(t, l.Reload())
+	requireIP(t, l, "10.0.0.2", "")
+}
@@ -27,6 +27,13 @@ type Transformer interface {
 	Transform(in config.GenericMap) (config.GenericMap, bool)
 }
 
+// MultiTransformer is implemented by the transformers that can emit any number of entries from a single one.
+// The pipeline calls TransformMulti rather than Transform for them.
+type MultiTransformer interface {
+	Transformer
+	TransformMulti(in config.GenericMap) []config.GenericMap
+}
+
 type transformNone struct {
 }
 
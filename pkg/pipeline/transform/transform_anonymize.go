@@ -0,0 +1,119 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/anonymize"
+	"github.com/sirupsen/logrus"
+)
+
+var alog = logrus.WithField("component", "transform.Anonymize")
+
+type Anonymize struct {
+	Rules      []api.TransformAnonymizeRule
+	anonymizer *anonymize.Anonymizer
+}
+
+// Transform anonymizes the configured fields of a flow. Values that can't be anonymized, such as
+// invalid IP or MAC addresses, are removed rather than left in clear.
+func (a *Anonymize) Transform(entry config.GenericMap) (config.GenericMap, bool) {
+	outputEntry := entry.Copy()
+	for i := range a.Rules {
+		rule := &a.Rules[i]
+		value, ok := entry[rule.Input]
+		if !ok {
+			continue
+		}
+		output := rule.Output
+		if output == "" {
+			output = rule.Input
+		}
+		anonymized, ok := a.anonymize(rule.Type, value)
+		if !ok {
+			alog.Tracef("can't anonymize %s value %v. Removing it", rule.Input, value)
+			delete(outputEntry, output)
+			continue
+		}
+		outputEntry[output] = anonymized
+	}
+	return outputEntry, true
+}
+
+func (a *Anonymize) anonymize(ruleType string, value interface{}) (string, bool) {
+	str, ok := value.(string)
+	if !ok {
+		if ruleType != api.OpHMAC || value == nil {
+			return "", false
+		}
+		str = fmt.Sprintf("%v", value)
+	}
+	switch ruleType {
+	case api.OpAnonymizeIP:
+		ip := net.ParseIP(str)
+		if ip == nil {
+			return "", false
+		}
+		return a.anonymizer.IP(ip).String(), true
+	case api.OpHashMAC, api.OpTruncateMAC:
+		mac, err := net.ParseMAC(str)
+		if err != nil {
+			return "", false
+		}
+		if ruleType == api.OpHashMAC {
+			return a.anonymizer.HashMAC(mac).String(), true
+		}
+		return anonymize.TruncateMAC(mac).String(), true
+	case api.OpHMAC:
+		return a.anonymizer.String(str), true
+	}
+	return "", false
+}
+
+// NewTransformAnonymize creates a new anonymize transform, loading its key from the configured file
+func NewTransformAnonymize(params config.StageParam) (Transformer, error) {
+	alog.Debugf("entering NewTransformAnonymize")
+	if params.Transform == nil || params.Transform.Anonymize == nil || params.Transform.Anonymize.KeyFile == "" {
+		return nil, errors.New("an anonymization key file is required")
+	}
+	cfg := params.Transform.Anonymize
+	for _, rule := range cfg.Rules {
+		if rule.Input == "" {
+			return nil, fmt.Errorf("missing input field in anonymize rule %+v", rule)
+		}
+		switch rule.Type {
+		case api.OpAnonymizeIP, api.OpHashMAC, api.OpTruncateMAC, api.OpHMAC:
+		default:
+			return nil, fmt.Errorf("unknown type %q for anonymize rule %+v", rule.Type, rule)
+		}
+	}
+	key, err := anonymize.LoadKey(cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	anonymizer, err := anonymize.NewAnonymizer(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Anonymize{Rules: cfg.Rules, anonymizer: anonymizer}, nil
+}
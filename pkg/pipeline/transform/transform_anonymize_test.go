@@ -0,0 +1,93 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"net"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func anonymizeParams(t *testing.T, rules ...api.TransformAnonymizeRule) config.StageParam {
+	keyFile := path.Join(t.TempDir(), "anonymize.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(strings.Repeat("0123456789abcdef", 4)), 0600))
+	return config.NewTransformAnonymizeParams("anonymize", api.TransformAnonymize{KeyFile: keyFile, Rules: rules})
+}
+
+func TestTransformAnonymize(t *testing.T) {
+	params := anonymizeParams(t,
+		api.TransformAnonymizeRule{Input: "SrcAddr", Type: api.OpAnonymizeIP},
+		api.TransformAnonymizeRule{Input: "DstAddr", Type: api.OpAnonymizeIP},
+		api.TransformAnonymizeRule{Input: "SrcMac", Type: api.OpHashMAC},
+		api.TransformAnonymizeRule{Input: "DstMac", Output: "DstMacVendor", Type: api.OpTruncateMAC},
+		api.TransformAnonymizeRule{Input: "SrcK8S_Name", Type: api.OpHMAC},
+		api.TransformAnonymizeRule{Input: "Missing", Type: api.OpHMAC},
+	)
+	tr, err := NewTransformAnonymize(params)
+	require.NoError(t, err)
+
+	entry := config.GenericMap{
+		"SrcAddr":     "10.1.2.3",
+		"DstAddr":     "2001:db8::1",
+		"SrcMac":      "00:1B:21:3A:4B:5C",
+		"DstMac":      "00:1b:21:3a:4b:5d",
+		"SrcK8S_Name": "web-5d8f7-abcde",
+		"Bytes":       1234,
+	}
+	output, ok := tr.Transform(entry)
+	require.True(t, ok)
+	require.NotEqual(t, "10.1.2.3", output["SrcAddr"])
+	anonymizedIP := net.ParseIP(output["DstAddr"].(string))
+	require.NotNil(t, anonymizedIP)
+	require.Nil(t, anonymizedIP.To4())
+	require.NotEqual(t, "2001:db8::1", output["DstAddr"])
+	require.NotEqual(t, "00:1b:21:3a:4b:5c", output["SrcMac"])
+	require.Equal(t, "00:1b:21:00:00:00", output["DstMacVendor"])
+	require.Equal(t, "00:1b:21:3a:4b:5d", output["DstMac"])
+	require.Len(t, output["SrcK8S_Name"], 32)
+	require.Equal(t, 1234, output["Bytes"])
+	require.NotContains(t, output, "Missing")
+	// the input entry isn't modified
+	require.Equal(t, "10.1.2.3", entry["SrcAddr"])
+
+	// deterministic across instances sharing the key
+	other, err := NewTransformAnonymize(params)
+	require.NoError(t, err)
+	otherOutput, _ := other.Transform(entry)
+	require.Equal(t, output, otherOutput)
+
+	// invalid values are removed
+	output, _ = tr.Transform(config.GenericMap{"SrcAddr": "not-an-ip", "SrcMac": 42})
+	require.NotContains(t, output, "SrcAddr")
+	require.NotContains(t, output, "SrcMac")
+}
+
+func TestNewTransformAnonymize_Invalid(t *testing.T) {
+	_, err := NewTransformAnonymize(config.NewTransformAnonymizeParams("anonymize", api.TransformAnonymize{}))
+	require.Error(t, err)
+	_, err = NewTransformAnonymize(anonymizeParams(t, api.TransformAnonymizeRule{Input: "SrcAddr", Type: "encrypt"}))
+	require.Error(t, err)
+	_, err = NewTransformAnonymize(anonymizeParams(t, api.TransformAnonymizeRule{Type: api.OpHMAC}))
+	require.Error(t, err)
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+
+	"github.com/Knetic/govaluate"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/netobserv/flowlogs-pipeline/pkg/operational"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	conditionResultTrue  = "true"
+	conditionResultFalse = "false"
+	conditionResultError = "error"
+)
+
+// ruleCondition is the compiled `if` predicate of a rule, along with the counters of its evaluations by result
+type ruleCondition struct {
+	expression  *govaluate.EvaluableExpression
+	evaluations map[string]prometheus.Counter
+	// cidrs holds the literal CIDRs of the inCIDR calls, parsed when the condition is compiled. It is read-only
+	// afterwards: the CIDRs read from fields are parsed on each evaluation.
+	cidrs map[string]*net.IPNet
+}
+
+// conditionParameters exposes the entry fields to the expressions. Missing fields are nil rather than an
+// error, so that they can be tested with has().
+type conditionParameters config.GenericMap
+
+func (p conditionParameters) Get(name string) (interface{}, error) {
+	return p[name], nil
+}
+
+// newRuleCondition compiles the predicate of the rule #index of the stage. Its functions are:
+// - has(field): whether the field is set
+// - inCIDR(ip, cidr...): whether the IP belongs to any of the CIDRs
+func newRuleCondition(opMetrics *operational.Metrics, stage string, index int, predicate string) (*ruleCondition, error) {
+	c := &ruleCondition{cidrs: map[string]*net.IPNet{}}
+	expression, err := govaluate.NewEvaluableExpressionWithFunctions(predicate, map[string]govaluate.ExpressionFunction{
+		"has":    conditionHas,
+		"inCIDR": c.inCIDR,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q of rule #%d: %w", predicate, index, err)
+	}
+	c.expression = expression
+	if err := c.parseLiteralCIDRs(); err != nil {
+		return nil, fmt.Errorf("invalid condition %q of rule #%d: %w", predicate, index, err)
+	}
+	rule := strconv.Itoa(index)
+	c.evaluations = map[string]prometheus.Counter{}
+	for _, result := range []string{conditionResultTrue, conditionResultFalse, conditionResultError} {
+		c.evaluations[result] = opMetrics.NewCounter(&ruleConditionEvaluationsCounter, stage, rule, result)
+	}
+	return c, nil
+}
+
+// newRuleConditions compiles the non-empty predicates, returning them by rule index
+func newRuleConditions(opMetrics *operational.Metrics, stage string, predicates []string) (map[int]*ruleCondition, error) {
+	conditions := map[int]*ruleCondition{}
+	for i, predicate := range predicates {
+		if predicate == "" {
+			continue
+		}
+		condition, err := newRuleCondition(opMetrics, stage, i, predicate)
+		if err != nil {
+			return nil, err
+		}
+		conditions[i] = condition
+	}
+	return conditions, nil
+}
+
+// matches evaluates the predicate against the entry. Evaluation errors, such as comparing a missing field
+// to a number, don't match.
+func (c *ruleCondition) matches(entry config.GenericMap) bool {
+	result, err := c.expression.Eval(conditionParameters(entry))
+	if err != nil {
+		logrus.WithError(err).Tracef("can't evaluate condition %s", c.expression.String())
+		c.evaluations[conditionResultError].Inc()
+		return false
+	}
+	if matched, ok := result.(bool); ok && matched {
+		c.evaluations[conditionResultTrue].Inc()
+		return true
+	}
+	c.evaluations[conditionResultFalse].Inc()
+	return false
+}
+
+// conditionHas is called without arguments when the field is nil
+func conditionHas(args ...interface{}) (interface{}, error) {
+	return len(args) == 1 && args[0] != nil, nil
+}
+
+func (c *ruleCondition) inCIDR(args ...interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("inCIDR expects an IP and at least one CIDR, got %d arguments", len(args))
+	}
+	strIP, ok := args[0].(string)
+	if !ok {
+		return false, nil
+	}
+	ip := net.ParseIP(strIP)
+	if ip == nil {
+		return false, nil
+	}
+	for _, arg := range args[1:] {
+		cidr, err := c.parseCIDR(arg)
+		if err != nil {
+			return nil, err
+		}
+		if cidr.Contains(ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// parseLiteralCIDRs parses the string literals passed as CIDRs to inCIDR, so that invalid ones are reported
+// when the condition is compiled
+func (c *ruleCondition) parseLiteralCIDRs() error {
+	inCIDR := reflect.ValueOf(c.inCIDR).Pointer()
+	tokens := c.expression.Tokens()
+	for i := range tokens {
+		if tokens[i].Kind != govaluate.FUNCTION || reflect.ValueOf(tokens[i].Value).Pointer() != inCIDR {
+			continue
+		}
+		// the arguments follow the opening clause, separated at depth 1
+		depth, arg := 0, 0
+		for j := i + 1; j < len(tokens); j++ {
+			switch tokens[j].Kind {
+			case govaluate.CLAUSE:
+				depth++
+				continue
+			case govaluate.CLAUSE_CLOSE:
+				depth--
+			case govaluate.SEPARATOR:
+				if depth == 1 {
+					arg++
+				}
+				continue
+			}
+			if depth == 0 {
+				break
+			}
+			// a single string token is a literal argument
+			literal, ok := tokens[j].Value.(string)
+			if !ok || tokens[j].Kind != govaluate.STRING || depth != 1 || arg == 0 || !isWholeArgument(tokens, j) {
+				continue
+			}
+			_, cidr, err := net.ParseCIDR(literal)
+			if err != nil {
+				return err
+			}
+			c.cidrs[literal] = cidr
+		}
+	}
+	return nil
+}
+
+// isWholeArgument checks that the token is a function argument by itself, rather than a part of an expression
+func isWholeArgument(tokens []govaluate.ExpressionToken, i int) bool {
+	before, after := tokens[i-1].Kind, tokens[i+1].Kind
+	return (before == govaluate.CLAUSE || before == govaluate.SEPARATOR) &&
+		(after == govaluate.CLAUSE_CLOSE || after == govaluate.SEPARATOR)
+}
+
+// parseCIDR returns the literal CIDRs parsed at compile time, and parses the other ones without caching them,
+// since they come from the records
+func (c *ruleCondition) parseCIDR(arg interface{}) (*net.IPNet, error) {
+	str, ok := arg.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid CIDR %v: not a string", arg)
+	}
+	if cidr, ok := c.cidrs[str]; ok {
+		return cidr, nil
+	}
+	_, cidr, err := net.ParseCIDR(str)
+	return cidr, err
+}
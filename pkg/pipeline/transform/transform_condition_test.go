@@ -0,0 +1,93 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RuleCondition(t *testing.T) {
+	conditions, err := newRuleConditions(opMetrics, "test_rule_condition", []string{
+		"has(SamplingRate)",
+		"",
+		"inCIDR(SrcAddr, '10.128.0.0/14', 'fd00::/8')",
+		"Proto == 6 && DstPort < 1024",
+		"!has(SamplingRate) || SamplingRate > 1",
+	})
+	require.NoError(t, err)
+	require.Len(t, conditions, 4)
+	require.NotContains(t, conditions, 1)
+
+	entry := config.GenericMap{"SamplingRate": 50, "SrcAddr": "10.130.1.2", "Proto": 6, "DstPort": 443}
+	require.True(t, conditions[0].matches(entry))
+	require.True(t, conditions[2].matches(entry))
+	require.True(t, conditions[3].matches(entry))
+	require.True(t, conditions[4].matches(entry))
+	// literal CIDRs are parsed when compiling
+	require.Len(t, conditions[2].cidrs, 2)
+
+	entry = config.GenericMap{"SrcAddr": "fd00::1", "Proto": 17, "DstPort": 53}
+	require.False(t, conditions[0].matches(entry))
+	require.True(t, conditions[2].matches(entry))
+	require.False(t, conditions[3].matches(entry))
+	require.True(t, conditions[4].matches(entry))
+
+	entry = config.GenericMap{"SamplingRate": 1, "SrcAddr": "192.168.0.1", "Proto": 6}
+	require.False(t, conditions[2].matches(entry))
+	// comparing the missing DstPort fails, which doesn't match
+	require.False(t, conditions[3].matches(entry))
+	require.False(t, conditions[4].matches(entry))
+
+	// missing or invalid IPs don't match
+	require.False(t, conditions[2].matches(config.GenericMap{}))
+	require.False(t, conditions[2].matches(config.GenericMap{"SrcAddr": "not-an-ip"}))
+}
+
+func Test_RuleConditionCounters(t *testing.T) {
+	condition, err := newRuleCondition(opMetrics, "test_rule_condition_counters", 0, "inCIDR(SrcAddr, Network)")
+	require.NoError(t, err)
+	counters := map[string]*fakeCounter{}
+	for result := range condition.evaluations {
+		counters[result] = &fakeCounter{}
+		condition.evaluations[result] = counters[result]
+	}
+	require.True(t, condition.matches(config.GenericMap{"SrcAddr": "10.0.0.1", "Network": "10.0.0.0/8"}))
+	require.False(t, condition.matches(config.GenericMap{"SrcAddr": "11.0.0.1", "Network": "10.0.0.0/8"}))
+	require.False(t, condition.matches(config.GenericMap{"SrcAddr": "11.0.0.1", "Network": "invalid"}))
+	require.False(t, condition.matches(config.GenericMap{"SrcAddr": "11.0.0.1", "Network": "invalid"}))
+	require.Equal(t, 1, counters[conditionResultTrue].count)
+	require.Equal(t, 1, counters[conditionResultFalse].count)
+	require.Equal(t, 2, counters[conditionResultError].count)
+	// CIDRs from the records aren't cached
+	require.Empty(t, condition.cidrs)
+}
+
+func Test_RuleConditionInvalid(t *testing.T) {
+	_, err := newRuleConditions(opMetrics, "test_rule_condition_invalid", []string{"has(SamplingRate)", "SrcAddr =="})
+	require.ErrorContains(t, err, "rule #1")
+	_, err = newRuleConditions(opMetrics, "test_rule_condition_invalid", []string{"unknown(SrcAddr)"})
+	require.Error(t, err)
+	// invalid literal CIDRs are reported when compiling
+	_, err = newRuleConditions(opMetrics, "test_rule_condition_invalid", []string{"has(Proto) && inCIDR(SrcAddr, '10.0.0.0/8', '10.0.0.0/33')"})
+	require.ErrorContains(t, err, "10.0.0.0/33")
+	_, err = newRuleConditions(opMetrics, "test_rule_condition_invalid", []string{"inCIDR(DstAddr, 'not-a-cidr') || Proto == 6"})
+	require.ErrorContains(t, err, "rule #0")
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+var elog = logrus.WithField("component", "transform.Explode")
+
+type Explode struct {
+	splits []explodeSplit
+	// splitFields are the input fields of all the splits, which are removed from the emitted records
+	splitFields []string
+	lists       []api.TransformExplodeList
+	indexField  string
+	// dropWarning logs once that Transform drops records
+	dropWarning sync.Once
+}
+
+// explodeSplit holds the fields of a split, with their templates resolved
+type explodeSplit struct {
+	fields []explodeField
+	set    map[string]interface{}
+}
+
+type explodeField struct {
+	input  string
+	output string
+}
+
+// Transform returns the first emitted record, for the callers expecting a single record: the other records are
+// dropped, which is logged once. The pipeline builder always calls TransformMulti instead.
+func (e *Explode) Transform(entry config.GenericMap) (config.GenericMap, bool) {
+	outputs := e.TransformMulti(entry)
+	if len(outputs) == 0 {
+		return nil, false
+	}
+	if len(outputs) > 1 {
+		e.dropWarning.Do(func() {
+			elog.Warnf("explode called through Transform: only the first of the %d records is kept, the next ones are dropped", len(outputs))
+		})
+	}
+	return outputs[0], true
+}
+
+// TransformMulti emits one record per split, or per list element
+func (e *Explode) TransformMulti(entry config.GenericMap) []config.GenericMap {
+	if len(e.lists) > 0 {
+		return e.explodeLists(entry)
+	}
+	return e.explodeSplits(entry)
+}
+
+// explodeSplits emits a record per split, in which the input fields of all the splits are replaced by the
+// output fields of the split
+func (e *Explode) explodeSplits(entry config.GenericMap) []config.GenericMap {
+	outputs := make([]config.GenericMap, 0, len(e.splits))
+	for i := range e.splits {
+		split := &e.splits[i]
+		output := entry.Copy()
+		for _, field := range e.splitFields {
+			delete(output, field)
+		}
+		for _, field := range split.fields {
+			if value, ok := entry[field.input]; ok {
+				output[field.output] = value
+			}
+		}
+		for field, value := range split.set {
+			output[field] = value
+		}
+		if e.indexField != "" {
+			output[e.indexField] = i
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs
+}
+
+// explodeLists emits a record per element of the lists, which are read in parallel: the record i gets the element
+// i of each list, when it has one. Records without any element are emitted unchanged, as a copy.
+func (e *Explode) explodeLists(entry config.GenericMap) []config.GenericMap {
+	lists := make([]reflect.Value, len(e.lists))
+	length := 0
+	for i := range e.lists {
+		value := reflect.ValueOf(entry[e.lists[i].Field])
+		if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+			continue
+		}
+		lists[i] = value
+		if value.Len() > length {
+			length = value.Len()
+		}
+	}
+	if length == 0 {
+		return []config.GenericMap{entry.Copy()}
+	}
+	outputs := make([]config.GenericMap, 0, length)
+	for index := 0; index < length; index++ {
+		output := entry.Copy()
+		for i := range e.lists {
+			list := &e.lists[i]
+			delete(output, list.Field)
+			if lists[i].IsValid() && index < lists[i].Len() {
+				output[list.GetOutput()] = lists[i].Index(index).Interface()
+			}
+		}
+		if e.indexField != "" {
+			output[e.indexField] = index
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs
+}
+
+// resolveTemplate replaces the {var} variables of the template by their values
+func resolveTemplate(template string, vars map[string]string) (string, error) {
+	resolved := template
+	for name, value := range vars {
+		resolved = strings.ReplaceAll(resolved, "{"+name+"}", value)
+	}
+	if strings.ContainsAny(resolved, "{}") {
+		return "", fmt.Errorf("template %q has unknown variables (available: %v)", template, vars)
+	}
+	return resolved, nil
+}
+
+func newExplodeSplits(cfg *api.TransformExplode) ([]explodeSplit, []string, error) {
+	var splits []explodeSplit
+	var splitFields []string
+	seen := map[string]struct{}{}
+	for i := range cfg.Splits {
+		split := explodeSplit{set: cfg.Splits[i].Set}
+		for j := range cfg.Fields {
+			field := &cfg.Fields[j]
+			if field.Input == "" || field.Output == "" {
+				return nil, nil, fmt.Errorf("invalid explode field %+v: input and output are required", *field)
+			}
+			input, err := resolveTemplate(field.Input, cfg.Splits[i].Vars)
+			if err != nil {
+				return nil, nil, fmt.Errorf("explode split #%d: %w", i, err)
+			}
+			output, err := resolveTemplate(field.Output, cfg.Splits[i].Vars)
+			if err != nil {
+				return nil, nil, fmt.Errorf("explode split #%d: %w", i, err)
+			}
+			split.fields = append(split.fields, explodeField{input: input, output: output})
+			if _, ok := seen[input]; !ok {
+				seen[input] = struct{}{}
+				splitFields = append(splitFields, input)
+			}
+		}
+		splits = append(splits, split)
+	}
+	return splits, splitFields, nil
+}
+
+// NewTransformExplode creates a new explode transform
+func NewTransformExplode(params config.StageParam) (Transformer, error) {
+	elog.Debugf("entering NewTransformExplode")
+	if params.Transform == nil || params.Transform.Explode == nil {
+		return nil, errors.New("missing explode configuration")
+	}
+	cfg := params.Transform.Explode
+	if (len(cfg.Splits) == 0) == (len(cfg.Lists) == 0) {
+		return nil, errors.New("explode requires either splits or lists")
+	}
+	if len(cfg.Lists) > 0 && len(cfg.Fields) > 0 {
+		return nil, errors.New("explode fields can only be used with splits")
+	}
+	for i := range cfg.Lists {
+		if cfg.Lists[i].Field == "" {
+			return nil, fmt.Errorf("missing field in explode list %+v", cfg.Lists[i])
+		}
+	}
+	splits, splitFields, err := newExplodeSplits(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Explode{
+		splits:      splits,
+		splitFields: splitFields,
+		lists:       cfg.Lists,
+		indexField:  cfg.IndexField,
+	}, nil
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformExplode_Splits(t *testing.T) {
+	tr, err := NewTransformExplode(config.NewTransformExplodeParams("explode", api.TransformExplode{
+		Splits: []api.TransformExplodeSplit{
+			{Vars: map[string]string{"dir": "AB", "a": "Src", "b": "Dst"}, Set: map[string]interface{}{"Direction": "AB"}},
+			{Vars: map[string]string{"dir": "BA", "a": "Dst", "b": "Src"}, Set: map[string]interface{}{"Direction": "BA"}},
+		},
+		Fields: []api.TransformExplodeField{
+			{Input: "Bytes_{dir}", Output: "Bytes"},
+			{Input: "Packets_{dir}", Output: "Packets"},
+			{Input: "{a}Addr", Output: "SrcAddr"},
+			{Input: "{b}Addr", Output: "DstAddr"},
+			{Input: "{a}Port", Output: "SrcPort"},
+			{Input: "{b}Port", Output: "DstPort"},
+		},
+		IndexField: "Split",
+	}))
+	require.NoError(t, err)
+	require.Implements(t, (*MultiTransformer)(nil), tr)
+
+	entry := config.GenericMap{
+		"SrcAddr": "10.0.0.1", "SrcPort": 45678, "DstAddr": "10.0.0.2", "DstPort": 443, "Proto": 6,
+		"Bytes_AB": 100, "Bytes_BA": 2000, "Packets_AB": 2,
+	}
+	outputs := tr.(MultiTransformer).TransformMulti(entry)
+	require.Equal(t, []config.GenericMap{{
+		"SrcAddr": "10.0.0.1", "SrcPort": 45678, "DstAddr": "10.0.0.2", "DstPort": 443, "Proto": 6,
+		"Bytes": 100, "Packets": 2, "Direction": "AB", "Split": 0,
+	}, {
+		"SrcAddr": "10.0.0.2", "SrcPort": 443, "DstAddr": "10.0.0.1", "DstPort": 45678, "Proto": 6,
+		"Bytes": 2000, "Direction": "BA", "Split": 1,
+	}}, outputs)
+	// the input is left unchanged
+	require.Equal(t, 100, entry["Bytes_AB"])
+
+	// single output callers get the first record
+	output, ok := tr.Transform(entry)
+	require.True(t, ok)
+	require.Equal(t, outputs[0], output)
+}
+
+func TestTransformExplode_Lists(t *testing.T) {
+	tr, err := NewTransformExplode(config.NewTransformExplodeParams("explode", api.TransformExplode{
+		Lists: []api.TransformExplodeList{
+			{Field: "Interfaces", Output: "Interface"},
+			{Field: "IfDirections", Output: "IfDirection"},
+		},
+		IndexField: "IfIndex",
+	}))
+	require.NoError(t, err)
+	explode := tr.(MultiTransformer)
+
+	outputs := explode.TransformMulti(config.GenericMap{
+		"Bytes":        100,
+		"Interfaces":   []string{"eth0", "br-ex", "genev_sys_6081"},
+		"IfDirections": []interface{}{0, 1},
+	})
+	require.Equal(t, []config.GenericMap{
+		{"Bytes": 100, "Interface": "eth0", "IfDirection": 0, "IfIndex": 0},
+		{"Bytes": 100, "Interface": "br-ex", "IfDirection": 1, "IfIndex": 1},
+		{"Bytes": 100, "Interface": "genev_sys_6081", "IfIndex": 2},
+	}, outputs)
+
+	// records without elements are emitted unchanged
+	entry := config.GenericMap{"Bytes": 100, "Interfaces": []string{}}
+	require.Equal(t, []config.GenericMap{entry}, explode.TransformMulti(entry))
+	entry = config.GenericMap{"Bytes": 100, "Interfaces": "eth0"}
+	outputs = explode.TransformMulti(entry)
+	require.Equal(t, []config.GenericMap{entry}, outputs)
+	// the emitted record is a copy
+	outputs[0]["Bytes"] = 200
+	require.Equal(t, 100, entry["Bytes"])
+}
+
+func TestTransformExplode_InvalidConfig(t *testing.T) {
+	for _, cfg := range []api.TransformExplode{
+		{},
+		{
+			Splits: []api.TransformExplodeSplit{{Vars: map[string]string{"dir": "AB"}}},
+			Lists:  []api.TransformExplodeList{{Field: "Interfaces"}},
+		},
+		{
+			Lists:  []api.TransformExplodeList{{Field: "Interfaces"}},
+			Fields: []api.TransformExplodeField{{Input: "Bytes", Output: "Bytes"}},
+		},
+		{Lists: []api.TransformExplodeList{{Output: "Interface"}}},
+		{
+			Splits: []api.TransformExplodeSplit{{Vars: map[string]string{"dir": "AB"}}},
+			Fields: []api.TransformExplodeField{{Input: "Bytes_{direction}", Output: "Bytes"}},
+		},
+		{
+			Splits: []api.TransformExplodeSplit{{Vars: map[string]string{"dir": "AB"}}},
+			Fields: []api.TransformExplodeField{{Input: "Bytes_{dir}"}},
+		},
+	} {
+		_, err := NewTransformExplode(config.NewTransformExplodeParams("explode", cfg))
+		require.Error(t, err, "config %+v", cfg)
+	}
+}
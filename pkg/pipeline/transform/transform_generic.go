@@ -20,6 +20,7 @@ package transform
 import (
 	"github.com/netobserv/flowlogs-pipeline/pkg/api"
 	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/netobserv/flowlogs-pipeline/pkg/operational"
 	"github.com/sirupsen/logrus"
 )
 
@@ -28,6 +29,8 @@ var glog = logrus.WithField("component", "transform.Generic")
 type Generic struct {
 	policy string
 	rules  []api.GenericTransformRule
+	// conditions holds the compiled `if` predicates by rule index
+	conditions map[int]*ruleCondition
 }
 
 // Transform transforms a flow to a new set of keys
@@ -40,7 +43,10 @@ func (g *Generic) Transform(entry config.GenericMap) (config.GenericMap, bool) {
 	} else {
 		outputEntry = config.GenericMap{}
 	}
-	for _, transformRule := range g.rules {
+	for i, transformRule := range g.rules {
+		if condition, ok := g.conditions[i]; ok && !condition.matches(entry) {
+			continue
+		}
 		if transformRule.Multiplier != 0 {
 			ok = g.performMultiplier(entry, transformRule, outputEntry)
 		} else {
@@ -86,7 +92,7 @@ func (g *Generic) performMultiplier(entry config.GenericMap, transformRule api.G
 }
 
 // NewTransformGeneric create a new transform
-func NewTransformGeneric(params config.StageParam) (Transformer, error) {
+func NewTransformGeneric(opMetrics *operational.Metrics, params config.StageParam) (Transformer, error) {
 	glog.Debugf("entering NewTransformGeneric")
 	genConfig := api.TransformGeneric{}
 	if params.Transform != nil && params.Transform.Generic != nil {
@@ -102,9 +108,18 @@ func NewTransformGeneric(params config.StageParam) (Transformer, error) {
 	default:
 		glog.Panicf("unknown policy %s for transform.generic", policy)
 	}
+	predicates := make([]string, len(rules))
+	for i := range rules {
+		predicates[i] = rules[i].If
+	}
+	conditions, err := newRuleConditions(opMetrics, params.Name, predicates)
+	if err != nil {
+		return nil, err
+	}
 	transformGeneric := &Generic{
-		policy: policy,
-		rules:  rules,
+		policy:     policy,
+		rules:      rules,
+		conditions: conditions,
 	}
 	glog.Debugf("transformGeneric = %v", transformGeneric)
 	return transformGeneric, nil
@@ -137,7 +137,7 @@ func InitNewTransformGeneric(t *testing.T, configFile string) Transformer {
 	require.NotNil(t, v)
 
 	configParams := cfg.Parameters[0]
-	newTransform, err := NewTransformGeneric(configParams)
+	newTransform, err := NewTransformGeneric(opMetrics, configParams)
 	require.NoError(t, err)
 	return newTransform
 }
@@ -213,7 +213,7 @@ parameters:
 	require.NotNil(t, v)
 
 	configParams := cfg.Parameters[0]
-	_, err := NewTransformGeneric(configParams)
+	_, err := NewTransformGeneric(opMetrics, configParams)
 	require.NoError(t, err)
 
 	var badConfig = []byte(`
@@ -232,3 +232,28 @@ parameters:
 	require.Nil(t, v)
 	require.Nil(t, cfg)
 }
+
+func Test_Transform_Conditions(t *testing.T) {
+	tr, err := NewTransformGeneric(opMetrics, config.StageParam{
+		Name: "test_generic_rule_conditions",
+		Transform: &config.Transform{
+			Generic: &api.TransformGeneric{
+				Policy: "replace_keys",
+				Rules: []api.GenericTransformRule{
+					{Input: "Bytes", Output: "Bytes", Multiplier: 10, If: "has(SamplingRate)"},
+					{Input: "Bytes", Output: "Bytes", If: "!has(SamplingRate)"},
+					{Input: "SrcAddr", Output: "SrcAddr"},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	output, ok := tr.Transform(config.GenericMap{"Bytes": 20, "SamplingRate": 10, "SrcAddr": "10.0.0.1"})
+	require.True(t, ok)
+	require.Equal(t, config.GenericMap{"Bytes": 200, "SrcAddr": "10.0.0.1"}, output)
+
+	output, ok = tr.Transform(config.GenericMap{"Bytes": 20, "SrcAddr": "10.0.0.1"})
+	require.True(t, ok)
+	require.Equal(t, config.GenericMap{"Bytes": 20, "SrcAddr": "10.0.0.1"}, output)
+}
@@ -0,0 +1,287 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/utils"
+	"github.com/sirupsen/logrus"
+)
+
+var llog = logrus.WithField("component", "transform.Lookup")
+
+// keySeparator separates the values of composite keys
+const keySeparator = "\x00"
+
+type Lookup struct {
+	api.TransformLookup
+	// cidrKey is the index of the key using CIDR matching, or -1
+	cidrKey int
+	// exactFields are the fields of the keys using exact matching
+	exactFields []string
+	// table holds a *lookupTable, which is atomically replaced when the file is reloaded
+	table atomic.Value
+}
+
+// lookupTable holds the rows of the table by their composite key. When a key uses CIDR matching, the rows are
+// grouped by the composite key of the other (exact) keys, in prefix trees matching the CIDR key.
+type lookupTable struct {
+	rows     map[string]map[string]interface{}
+	cidrRows map[string]*utils.PrefixTree
+}
+
+// Transform copies the columns of the row matching the record keys into the record, or their default values
+// when no row matches
+func (l *Lookup) Transform(entry config.GenericMap) (config.GenericMap, bool) {
+	outputEntry := entry.Copy()
+	row := l.lookup(entry)
+	for i := range l.Columns {
+		column := &l.Columns[i]
+		if value, ok := row[column.Column]; ok {
+			outputEntry[column.GetOutput()] = value
+		} else if column.Default != nil {
+			outputEntry[column.GetOutput()] = column.Default
+		}
+	}
+	return outputEntry, true
+}
+
+func (l *Lookup) lookup(entry config.GenericMap) map[string]interface{} {
+	table, _ := l.table.Load().(*lookupTable)
+	key, ok := exactKey(entry, l.exactFields)
+	if !ok {
+		return nil
+	}
+	if l.cidrKey < 0 {
+		return table.rows[key]
+	}
+	tree, ok := table.cidrRows[key]
+	if !ok {
+		return nil
+	}
+	ip := net.ParseIP(keyString(entry[l.Keys[l.cidrKey].Field]))
+	if ip == nil {
+		return nil
+	}
+	row, _ := tree.LookupIP(ip)
+	values, _ := row.(map[string]interface{})
+	return values
+}
+
+// exactKey returns the composite key made of the values of the exact keys fields, or columns for table rows.
+// It fails when any value is missing.
+func exactKey(values map[string]interface{}, names []string) (string, bool) {
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value, ok := values[name]
+		if !ok || value == nil {
+			return "", false
+		}
+		parts = append(parts, keyString(value))
+	}
+	return strings.Join(parts, keySeparator), true
+}
+
+// keyString formats the key values, so that numbers match whatever their type, e.g. the integers of the records
+// and the floats of the JSON tables
+func keyString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(value)
+}
+
+func (l *Lookup) reloadTable() {
+	table, err := buildLookupTable(&l.TransformLookup, l.cidrKey)
+	if err != nil {
+		llog.WithError(err).Error("can't reload lookup table. Keeping previous one")
+		return
+	}
+	l.table.Store(table)
+	llog.WithField("file", l.File).Info("lookup table reloaded")
+}
+
+func buildLookupTable(cfg *api.TransformLookup, cidrKey int) (*lookupTable, error) {
+	rows, err := readLookupRows(cfg.File)
+	if err != nil {
+		return nil, fmt.Errorf("reading lookup table %q: %w", cfg.File, err)
+	}
+	var exactColumns []string
+	for i := range cfg.Keys {
+		if i != cidrKey {
+			exactColumns = append(exactColumns, cfg.Keys[i].GetColumn())
+		}
+	}
+	table := &lookupTable{
+		rows:     map[string]map[string]interface{}{},
+		cidrRows: map[string]*utils.PrefixTree{},
+	}
+	// inserting in reverse order so that first rows override the latter
+	for i := len(rows) - 1; i >= 0; i-- {
+		row := rows[i]
+		key, ok := exactKey(row, exactColumns)
+		if !ok || (cidrKey >= 0 && row[cfg.Keys[cidrKey].GetColumn()] == nil) {
+			return nil, fmt.Errorf("lookup table %q, row %d: missing key column", cfg.File, i+1)
+		}
+		if cidrKey < 0 {
+			table.rows[key] = row
+			continue
+		}
+		cidr, err := parseCIDROrIP(keyString(row[cfg.Keys[cidrKey].GetColumn()]))
+		if err != nil {
+			return nil, fmt.Errorf("lookup table %q, row %d: %w", cfg.File, i+1, err)
+		}
+		tree, ok := table.cidrRows[key]
+		if !ok {
+			tree = utils.NewPrefixTree()
+			table.cidrRows[key] = tree
+		}
+		tree.InsertCIDR(cidr, row)
+	}
+	return table, nil
+}
+
+// parseCIDROrIP parses a CIDR, or an IP as a single address network
+func parseCIDROrIP(value string) (*net.IPNet, error) {
+	if ip := net.ParseIP(value); ip != nil {
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			bits = 8 * net.IPv4len
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, cidr, err := net.ParseCIDR(value)
+	return cidr, err
+}
+
+// readLookupRows reads a CSV file (with .csv extension), whose first line contains the column names, or a
+// JSON array of objects (any other extension). Empty CSV cells are ignored, as if the column was missing.
+func readLookupRows(path string) ([]map[string]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if !strings.EqualFold(filepath.Ext(path), ".csv") {
+		var rows []map[string]interface{}
+		if err := json.NewDecoder(f).Decode(&rows); err != nil {
+			return nil, err
+		}
+		return rows, nil
+	}
+	reader := csv.NewReader(f)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("missing header line")
+	}
+	header := records[0]
+	rows := make([]map[string]interface{}, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := map[string]interface{}{}
+		for i, value := range record {
+			if value = strings.TrimSpace(value); value != "" {
+				row[strings.TrimSpace(header[i])] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func validateLookupConfig(cfg *api.TransformLookup) (int, error) {
+	if cfg.File == "" {
+		return 0, errors.New("a lookup table file is required")
+	}
+	if len(cfg.Keys) == 0 {
+		return 0, errors.New("at least one lookup key is required")
+	}
+	cidrKey := -1
+	for i := range cfg.Keys {
+		key := &cfg.Keys[i]
+		if key.Field == "" {
+			return 0, fmt.Errorf("missing field in lookup key %+v", *key)
+		}
+		switch key.GetMatch() {
+		case api.LookupMatchExact:
+		case api.LookupMatchCIDR:
+			if cidrKey >= 0 {
+				return 0, fmt.Errorf("only one lookup key can use '%s' matching", api.LookupMatchCIDR)
+			}
+			cidrKey = i
+		default:
+			return 0, fmt.Errorf("unknown match %q in lookup key %+v, expected '%s' or '%s'", key.Match, *key, api.LookupMatchExact, api.LookupMatchCIDR)
+		}
+	}
+	if len(cfg.Columns) == 0 {
+		return 0, errors.New("at least one lookup column is required")
+	}
+	for i := range cfg.Columns {
+		if cfg.Columns[i].Column == "" {
+			return 0, fmt.Errorf("missing column name in lookup column %+v", cfg.Columns[i])
+		}
+	}
+	return cidrKey, nil
+}
+
+// NewTransformLookup creates a new lookup transform, loading the table file and watching it for changes
+func NewTransformLookup(params config.StageParam) (Transformer, error) {
+	llog.Debugf("entering NewTransformLookup")
+	if params.Transform == nil || params.Transform.Lookup == nil {
+		return nil, errors.New("missing lookup configuration")
+	}
+	cfg := params.Transform.Lookup
+	cidrKey, err := validateLookupConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	table, err := buildLookupTable(cfg, cidrKey)
+	if err != nil {
+		return nil, err
+	}
+	lookup := &Lookup{TransformLookup: *cfg, cidrKey: cidrKey}
+	for i := range cfg.Keys {
+		if i != cidrKey {
+			lookup.exactFields = append(lookup.exactFields, cfg.Keys[i].Field)
+		}
+	}
+	lookup.table.Store(table)
+	utils.WatchFiles([]string{cfg.File}, cfg.GetReloadPeriod(), lookup.reloadTable)
+	return lookup, nil
+}
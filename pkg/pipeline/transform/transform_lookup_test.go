@@ -0,0 +1,198 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLookupTable(t *testing.T, name, content string) string {
+	file := path.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(file, []byte(content), 0600))
+	return file
+}
+
+func TestTransformLookup_CompositeKey(t *testing.T) {
+	file := writeLookupTable(t, "interfaces.csv", `exporter,ifIndex,name,speed
+# core router
+10.0.0.1,1,eth0,10G
+10.0.0.1,2,eth1,
+10.0.0.2,1,ge-0/0/0,1G
+10.0.0.1,1,duplicate,1G
+`)
+	tr, err := NewTransformLookup(config.NewTransformLookupParams("lookup", api.TransformLookup{
+		File: file,
+		Keys: []api.TransformLookupKey{
+			{Field: "AgentIP", Column: "exporter"},
+			{Field: "InIf", Column: "ifIndex"},
+		},
+		Columns: []api.TransformLookupColumn{
+			{Column: "name", Output: "InIfName", Default: "unknown"},
+			{Column: "speed", Output: "InIfSpeed"},
+		},
+	}))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		entry    config.GenericMap
+		expected config.GenericMap
+	}{{
+		name:     "first row wins, numbers match",
+		entry:    config.GenericMap{"AgentIP": "10.0.0.1", "InIf": 1},
+		expected: config.GenericMap{"AgentIP": "10.0.0.1", "InIf": 1, "InIfName": "eth0", "InIfSpeed": "10G"},
+	}, {
+		name:     "float from JSON records",
+		entry:    config.GenericMap{"AgentIP": "10.0.0.2", "InIf": float64(1)},
+		expected: config.GenericMap{"AgentIP": "10.0.0.2", "InIf": float64(1), "InIfName": "ge-0/0/0", "InIfSpeed": "1G"},
+	}, {
+		name:     "empty cell",
+		entry:    config.GenericMap{"AgentIP": "10.0.0.1", "InIf": uint32(2)},
+		expected: config.GenericMap{"AgentIP": "10.0.0.1", "InIf": uint32(2), "InIfName": "eth1"},
+	}, {
+		name:     "not found",
+		entry:    config.GenericMap{"AgentIP": "10.0.0.2", "InIf": 2},
+		expected: config.GenericMap{"AgentIP": "10.0.0.2", "InIf": 2, "InIfName": "unknown"},
+	}, {
+		name:     "missing key field",
+		entry:    config.GenericMap{"AgentIP": "10.0.0.1"},
+		expected: config.GenericMap{"AgentIP": "10.0.0.1", "InIfName": "unknown"},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			output, ok := tr.Transform(tc.entry)
+			require.True(t, ok)
+			require.Equal(t, tc.expected, output)
+		})
+	}
+}
+
+func TestTransformLookup_CIDR(t *testing.T) {
+	file := writeLookupTable(t, "datacenters.json", `[
+  {"vlan": 10, "subnet": "10.0.0.0/8", "datacenter": "dc1", "tenant": {"name": "acme"}},
+  {"vlan": 10, "subnet": "10.1.0.0/16", "datacenter": "dc2"},
+  {"vlan": 20, "subnet": "10.1.2.3", "datacenter": "dc3"}
+]`)
+	tr, err := NewTransformLookup(config.NewTransformLookupParams("lookup", api.TransformLookup{
+		File: file,
+		Keys: []api.TransformLookupKey{
+			{Field: "Vlan", Column: "vlan"},
+			{Field: "SrcAddr", Column: "subnet", Match: api.LookupMatchCIDR},
+		},
+		Columns: []api.TransformLookupColumn{
+			{Column: "datacenter", Output: "SrcDatacenter"},
+			{Column: "tenant", Output: "SrcTenant"},
+		},
+	}))
+	require.NoError(t, err)
+
+	output, _ := tr.Transform(config.GenericMap{"Vlan": 10, "SrcAddr": "10.2.0.1"})
+	require.Equal(t, "dc1", output["SrcDatacenter"])
+	require.Equal(t, map[string]interface{}{"name": "acme"}, output["SrcTenant"])
+	// most specific CIDR
+	output, _ = tr.Transform(config.GenericMap{"Vlan": 10, "SrcAddr": "10.1.2.3"})
+	require.Equal(t, "dc2", output["SrcDatacenter"])
+	require.NotContains(t, output, "SrcTenant")
+	// single IP
+	output, _ = tr.Transform(config.GenericMap{"Vlan": 20, "SrcAddr": "10.1.2.3"})
+	require.Equal(t, "dc3", output["SrcDatacenter"])
+	output, _ = tr.Transform(config.GenericMap{"Vlan": 20, "SrcAddr": "10.1.2.4"})
+	require.NotContains(t, output, "SrcDatacenter")
+	// invalid IP
+	output, _ = tr.Transform(config.GenericMap{"Vlan": 10, "SrcAddr": "invalid"})
+	require.NotContains(t, output, "SrcDatacenter")
+}
+
+func TestTransformLookup_Reload(t *testing.T) {
+	file := writeLookupTable(t, "agents.csv", "agent,datacenter\n10.0.0.1,dc1\n")
+	tr, err := NewTransformLookup(config.NewTransformLookupParams("lookup", api.TransformLookup{
+		File:    file,
+		Keys:    []api.TransformLookupKey{{Field: "AgentIP", Column: "agent"}},
+		Columns: []api.TransformLookupColumn{{Column: "datacenter"}},
+	}))
+	require.NoError(t, err)
+	lookup := tr.(*Lookup)
+	output, _ := tr.Transform(config.GenericMap{"AgentIP": "10.0.0.1"})
+	require.Equal(t, "dc1", output["datacenter"])
+
+	require.NoError(t, os.WriteFile(file, []byte("agent,datacenter\n10.0.0.1,dc2\n"), 0600))
+	lookup.reloadTable()
+	output, _ = tr.Transform(config.GenericMap{"AgentIP": "10.0.0.1"})
+	require.Equal(t, "dc2", output["datacenter"])
+
+	// invalid content keeps the previous table
+	require.NoError(t, os.WriteFile(file, []byte("agent,datacenter\n10.0.0.1\n"), 0600))
+	lookup.reloadTable()
+	output, _ = tr.Transform(config.GenericMap{"AgentIP": "10.0.0.1"})
+	require.Equal(t, "dc2", output["datacenter"])
+}
+
+func TestNewTransformLookup_Validation(t *testing.T) {
+	file := writeLookupTable(t, "table.csv", "subnet,name\n10.0.0.0/8,internal\nnot-a-cidr,invalid\n")
+	for _, tc := range []struct {
+		name   string
+		config api.TransformLookup
+		err    string
+	}{{
+		name:   "missing file",
+		config: api.TransformLookup{Keys: []api.TransformLookupKey{{Field: "a"}}, Columns: []api.TransformLookupColumn{{Column: "b"}}},
+		err:    "file is required",
+	}, {
+		name:   "missing keys",
+		config: api.TransformLookup{File: file, Columns: []api.TransformLookupColumn{{Column: "name"}}},
+		err:    "at least one lookup key",
+	}, {
+		name: "several CIDR keys",
+		config: api.TransformLookup{File: file, Columns: []api.TransformLookupColumn{{Column: "name"}}, Keys: []api.TransformLookupKey{
+			{Field: "SrcAddr", Match: api.LookupMatchCIDR}, {Field: "DstAddr", Match: api.LookupMatchCIDR},
+		}},
+		err: "only one lookup key",
+	}, {
+		name: "unknown match",
+		config: api.TransformLookup{File: file, Columns: []api.TransformLookupColumn{{Column: "name"}}, Keys: []api.TransformLookupKey{
+			{Field: "SrcAddr", Match: "regex"},
+		}},
+		err: "unknown match",
+	}, {
+		name:   "missing columns",
+		config: api.TransformLookup{File: file, Keys: []api.TransformLookupKey{{Field: "SrcAddr", Column: "subnet"}}},
+		err:    "at least one lookup column",
+	}, {
+		name: "invalid CIDR",
+		config: api.TransformLookup{File: file, Columns: []api.TransformLookupColumn{{Column: "name"}}, Keys: []api.TransformLookupKey{
+			{Field: "SrcAddr", Column: "subnet", Match: api.LookupMatchCIDR},
+		}},
+		err: "row 2",
+	}, {
+		name: "missing key column",
+		config: api.TransformLookup{File: file, Columns: []api.TransformLookupColumn{{Column: "name"}}, Keys: []api.TransformLookupKey{
+			{Field: "SrcAddr", Column: "cidr"},
+		}},
+		err: "missing key column",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewTransformLookup(config.NewTransformLookupParams("lookup", tc.config))
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -21,173 +21,263 @@ import (
 	"fmt"
 	"net"
 	"os"
-	"regexp"
-	"strconv"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/Knetic/govaluate"
 	"github.com/netobserv/flowlogs-pipeline/pkg/api"
 	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/netobserv/flowlogs-pipeline/pkg/operational"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/asn"
 	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/kubernetes"
 	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/location"
 	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/netdb"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/oui"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/rdns"
 	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/utils"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
+	k8sclient "k8s.io/client-go/kubernetes"
 )
 
 var log = logrus.WithField("component", "transform.Network")
 
 type Network struct {
 	api.TransformNetwork
-	svcNames   *netdb.ServiceNames
-	categories []subnetCategory
-	ipCatCache *utils.TimedCache
-}
-
-type subnetCategory struct {
-	cidrs []*net.IPNet
-	name  string
+	svcNames *netdb.ServiceNames
+	// catMu guards categories, which are replaced when categories files are reloaded, along with ipCatCache so
+	// that a category looked up in replaced categories is never cached
+	catMu        sync.RWMutex
+	categories   *utils.PrefixTree
+	ipCatCache   *utils.TimedCache
+	subnetLabels *utils.PrefixTree
+	rdns         *rdns.Resolver
+	asnDB        *asn.DB
+	macVendors   *oui.DB
+	threatLists  []threatList
+	// rules are the compiled Rules
+	rules []networkRule
+	// kubeClusters is only set when several Kubernetes clusters are configured. Otherwise, kubeInventory is used
+	// when an inventory file is configured, and kubernetes.Data is used when it isn't
+	kubeClusters  *kubernetes.Clusters
+	kubeInventory *kubernetes.InventoryData
+	// policies is only set for a single cluster. Otherwise, each cluster in kubeClusters has its own.
+	policies *kubernetes.PolicyEvaluator
+	// kubeHistoryLookups counts the Kubernetes lookups resolved from the history of deleted Pods
+	kubeHistoryLookups prometheus.Counter
+	// locationDB is only set when a local location database is configured. Otherwise, the downloaded one is used
+	locationDB *location.DB
 }
 
 func (n *Network) Transform(inputEntry config.GenericMap) (config.GenericMap, bool) {
 	// copy input entry before transform to avoid alteration on parallel stages
 	outputEntry := inputEntry.Copy()
 
-	// TODO: for efficiency and maintainability, maybe each case in the switch below should be an individual implementation of Transformer
-	for _, rule := range n.Rules {
-		switch rule.Type {
-		case api.OpAddRegexIf:
-			matched, err := regexp.MatchString(rule.Parameters, fmt.Sprintf("%s", outputEntry[rule.Input]))
-			if err != nil {
-				continue
-			}
-			if matched {
-				outputEntry[rule.Output] = outputEntry[rule.Input]
-				outputEntry[rule.Output+"_Matched"] = true
-			}
-		case api.OpAddIf:
-			expressionString := fmt.Sprintf("val %s", rule.Parameters)
-			expression, err := govaluate.NewEvaluableExpression(expressionString)
-			if err != nil {
-				log.Warningf("Can't evaluate AddIf rule: %+v expression: %v. err %v", rule, expressionString, err)
-				continue
-			}
-			result, evaluateErr := expression.Evaluate(map[string]interface{}{"val": outputEntry[rule.Input]})
-			if evaluateErr == nil && result.(bool) {
-				if rule.Assignee != "" {
-					outputEntry[rule.Output] = rule.Assignee
-				} else {
-					outputEntry[rule.Output] = outputEntry[rule.Input]
-				}
-				outputEntry[rule.Output+"_Evaluate"] = true
-			}
-		case api.OpAddSubnet:
-			_, ipv4Net, err := net.ParseCIDR(fmt.Sprintf("%v%s", outputEntry[rule.Input], rule.Parameters))
-			if err != nil {
-				log.Warningf("Can't find subnet for IP %v and prefix length %s - err %v", outputEntry[rule.Input], rule.Parameters, err)
-				continue
-			}
-			outputEntry[rule.Output] = ipv4Net.String()
-		case api.OpAddLocation:
-			var locationInfo *location.Info
-			err, locationInfo := location.GetLocation(fmt.Sprintf("%s", outputEntry[rule.Input]))
-			if err != nil {
-				log.Warningf("Can't find location for IP %v err %v", outputEntry[rule.Input], err)
-				continue
-			}
-			outputEntry[rule.Output+"_CountryName"] = locationInfo.CountryName
-			outputEntry[rule.Output+"_CountryLongName"] = locationInfo.CountryLongName
-			outputEntry[rule.Output+"_RegionName"] = locationInfo.RegionName
-			outputEntry[rule.Output+"_CityName"] = locationInfo.CityName
-			outputEntry[rule.Output+"_Latitude"] = locationInfo.Latitude
-			outputEntry[rule.Output+"_Longitude"] = locationInfo.Longitude
-		case api.OpAddService:
-			protocol := fmt.Sprintf("%v", outputEntry[rule.Parameters])
-			portNumber, err := strconv.Atoi(fmt.Sprintf("%v", outputEntry[rule.Input]))
-			if err != nil {
-				log.Errorf("Can't convert port to int: Port %v - err %v", outputEntry[rule.Input], err)
-				continue
-			}
-			var serviceName string
-			protocolAsNumber, err := strconv.Atoi(protocol)
-			if err == nil {
-				// protocol has been submitted as number
-				serviceName = n.svcNames.ByPortAndProtocolNumber(portNumber, protocolAsNumber)
-			} else {
-				// protocol has been submitted as any string
-				serviceName = n.svcNames.ByPortAndProtocolName(portNumber, protocol)
-			}
-			if serviceName == "" {
-				if err != nil {
-					log.Debugf("Can't find service name for Port %v and protocol %v - err %v", outputEntry[rule.Input], protocol, err)
-					continue
-				}
-			}
-			outputEntry[rule.Output] = serviceName
-		case api.OpAddKubernetes:
-			kubeInfo, err := kubernetes.Data.GetInfo(fmt.Sprintf("%s", outputEntry[rule.Input]))
-			if err != nil {
-				logrus.WithError(err).Tracef("can't find kubernetes info for IP %v", outputEntry[rule.Input])
-				continue
-			}
-			// NETOBSERV-666: avoid putting empty namespaces or Loki aggregation queries will
-			// differentiate between empty and nil namespaces.
-			if kubeInfo.Namespace != "" {
-				outputEntry[rule.Output+"_Namespace"] = kubeInfo.Namespace
-			}
-			outputEntry[rule.Output+"_Name"] = kubeInfo.Name
-			outputEntry[rule.Output+"_Type"] = kubeInfo.Type
-			outputEntry[rule.Output+"_OwnerName"] = kubeInfo.Owner.Name
-			outputEntry[rule.Output+"_OwnerType"] = kubeInfo.Owner.Type
-			if rule.Parameters != "" {
-				for labelKey, labelValue := range kubeInfo.Labels {
-					outputEntry[rule.Parameters+"_"+labelKey] = labelValue
-				}
-			}
-			if kubeInfo.HostIP != "" {
-				outputEntry[rule.Output+"_HostIP"] = kubeInfo.HostIP
-				if kubeInfo.HostName != "" {
-					outputEntry[rule.Output+"_HostName"] = kubeInfo.HostName
-				}
-			}
-		case api.OpReinterpretDirection:
-			reinterpretDirection(outputEntry, &n.DirectionInfo)
-		case api.OpAddIPCategory:
-			if strIP, ok := outputEntry[rule.Input].(string); ok {
-				cat, ok := n.ipCatCache.GetCacheEntry(strIP)
-				if !ok {
-					cat = n.categorizeIP(net.ParseIP(strIP))
-					n.ipCatCache.UpdateCacheEntry(strIP, cat)
-				}
-				outputEntry[rule.Output] = cat
-			}
-
-		default:
-			log.Panicf("unknown type %s for transform.Network rule: %v", rule.Type, rule)
+	for i := range n.rules {
+		rule := &n.rules[i]
+		if rule.condition != nil && !rule.condition.matches(outputEntry) {
+			continue
 		}
+		rule.apply(outputEntry)
 	}
 
 	return outputEntry, true
 }
 
-func (n *Network) categorizeIP(ip net.IP) string {
-	if ip != nil {
-		for _, subnetCat := range n.categories {
-			for _, cidr := range subnetCat.cidrs {
-				if cidr.Contains(ip) {
-					return subnetCat.name
-				}
-			}
+// kubeSource provides the Kubernetes metadata of a single cluster
+type kubeSource interface {
+	GetInfo(string, time.Time) (*kubernetes.Info, error)
+	GetServiceEndpoints(string, int, string) (*kubernetes.ServiceEndpoints, error)
+}
+
+// kubeData returns the Kubernetes metadata source, when a single cluster is configured
+func (n *Network) kubeData() kubeSource {
+	if n.kubeInventory != nil {
+		return n.kubeInventory
+	}
+	return kubernetes.Data
+}
+
+func (n *Network) getKubeInfo(entry config.GenericMap, ip string) (*kubernetes.Info, string, error) {
+	timestamp := n.flowTime(entry)
+	var info *kubernetes.Info
+	var cluster string
+	var err error
+	if n.kubeClusters == nil {
+		info, err = n.kubeData().GetInfo(ip, timestamp)
+	} else {
+		info, cluster, err = n.kubeClusters.GetInfo(entry[n.Kubernetes.ClusterField], ip, timestamp)
+	}
+	if err == nil && info.FromHistory && n.kubeHistoryLookups != nil {
+		n.kubeHistoryLookups.Inc()
+	}
+	return info, cluster, err
+}
+
+// addServiceEndpoints adds the Service backing a Pod IP and port, or the candidate endpoints of a Service IP
+func (n *Network) addServiceEndpoints(entry config.GenericMap, input, serviceNameField, endpointsField string) {
+	cfg := n.Kubernetes.ServiceEndpoints
+	ip := fieldString(entry[input])
+	port, _ := intField(entry, cfg.GetPortField(input))
+	protocol := kubeProtocol(entry[cfg.GetProtocolField()])
+	var endpoints *kubernetes.ServiceEndpoints
+	var err error
+	if n.kubeClusters == nil {
+		endpoints, err = n.kubeData().GetServiceEndpoints(ip, port, protocol)
+	} else {
+		endpoints, err = n.kubeClusters.GetServiceEndpoints(entry[n.Kubernetes.ClusterField], ip, port, protocol)
+	}
+	if err != nil {
+		logrus.WithError(err).Tracef("can't find kubernetes service for IP %v and port %d", ip, port)
+		return
+	}
+	entry[serviceNameField] = endpoints.ServiceName
+	if endpoints.Endpoints != nil {
+		entry[endpointsField] = endpoints.Endpoints
+	}
+}
+
+// addNetworkPolicy evaluates the flow against the NetworkPolicies. Flows between non-Pod IPs are ignored.
+func (n *Network) addNetworkPolicy(entry config.GenericMap, prefix string) {
+	info := n.NetworkPolicyInfo
+	srcIP, _ := entry[info.GetSrcIPField()].(string)
+	dstIP, _ := entry[info.GetDstIPField()].(string)
+	if srcIP == "" || dstIP == "" {
+		return
+	}
+	port, _ := intField(entry, info.GetDstPortField())
+	protocol := kubeProtocol(entry[info.GetProtocolField()])
+	timestamp := n.flowTime(entry)
+	var result *kubernetes.PolicyResult
+	if n.kubeClusters == nil {
+		result = n.policies.Evaluate(srcIP, dstIP, port, protocol, timestamp)
+	} else {
+		var err error
+		result, err = n.kubeClusters.EvaluatePolicies(entry[n.Kubernetes.ClusterField], srcIP, dstIP, port, protocol, timestamp)
+		if err != nil {
+			logrus.WithError(err).Tracef("can't evaluate NetworkPolicies for flow %s -> %s", srcIP, dstIP)
+			return
 		}
 	}
+	if result == nil {
+		return
+	}
+	entry[prefix+"PolicyVerdict"] = result.Verdict
+	if len(result.Policies) > 0 {
+		entry[prefix+"PolicyName"] = strings.Join(result.Policies, ",")
+	}
+}
+
+// kubeProtocol returns the protocol name used by Kubernetes (TCP, UDP or SCTP) from a protocol
+// name or number, or an empty string for other protocols
+func kubeProtocol(value interface{}) string {
+	if name, ok := value.(string); ok {
+		switch upper := strings.ToUpper(name); upper {
+		case "TCP", "UDP", "SCTP":
+			return upper
+		}
+	}
+	if value == nil {
+		return ""
+	}
+	number, err := utils.ConvertToFloat64(value)
+	if err != nil {
+		return ""
+	}
+	switch int(number) {
+	case 6:
+		return "TCP"
+	case 17:
+		return "UDP"
+	case 132:
+		return "SCTP"
+	}
 	return ""
 }
 
+// flowTime returns the time of the flow, so that IPs are looked up in Kubernetes as they were assigned
+// at that time, or a zero time if the entry doesn't provide it
+func (n *Network) flowTime(entry config.GenericMap) time.Time {
+	value, ok := entry[n.Kubernetes.GetTimestampField()]
+	if !ok || value == nil {
+		return time.Time{}
+	}
+	ms, err := utils.ConvertToFloat64(value)
+	if err != nil || ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(int64(ms))
+}
+
+func loadServiceOverrides(servicesDB *netdb.ServiceNames, cfg *api.TransformNetwork) error {
+	if cfg.ServiceOverridesFile != "" {
+		overrides, err := os.Open(cfg.ServiceOverridesFile)
+		if err != nil {
+			return fmt.Errorf("opening service overrides file %q: %w", cfg.ServiceOverridesFile, err)
+		}
+		defer overrides.Close()
+		if err := servicesDB.LoadOverrides(overrides); err != nil {
+			return fmt.Errorf("loading service overrides file %q: %w", cfg.ServiceOverridesFile, err)
+		}
+	}
+	// inline overrides take precedence over the ones from the file
+	for _, override := range cfg.ServiceOverrides {
+		if override.Name == "" || override.Port <= 0 {
+			return fmt.Errorf("invalid service override %+v: name and port are required", override)
+		}
+		if err := servicesDB.AddOverride(override.Port, override.Protocol, override.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// categorize returns the category of the IP, using the cache
+func (n *Network) categorize(strIP string) string {
+	n.catMu.RLock()
+	defer n.catMu.RUnlock()
+	if cat, ok := n.ipCatCache.GetCacheEntry(strIP); ok {
+		return cat.(string)
+	}
+	cat := categorizeIP(n.categories, net.ParseIP(strIP))
+	n.ipCatCache.UpdateCacheEntry(strIP, cat)
+	return cat
+}
+
+func categorizeIP(categories *utils.PrefixTree, ip net.IP) string {
+	if ip != nil && categories != nil {
+		if cat, ok := categories.LookupIP(ip); ok {
+			return cat.(string)
+		}
+	}
+	return ""
+}
+
+// reloadIPCategories replaces the IP categories with the current content of the configured
+// files. The current categories are kept in case of error.
+func (n *Network) reloadIPCategories() {
+	categories, err := buildIPCategories(n.IPCategories, n.IPCategoriesFiles)
+	if err != nil {
+		log.WithError(err).Error("can't reload IP categories. Keeping previous ones")
+		return
+	}
+	n.catMu.Lock()
+	n.categories = categories
+	n.ipCatCache.Clear()
+	n.catMu.Unlock()
+	log.WithField("cidrs", categories.Len()).Info("IP categories reloaded")
+}
+
 // NewTransformNetwork create a new transform
-func NewTransformNetwork(params config.StageParam) (Transformer, error) {
+func NewTransformNetwork(opMetrics *operational.Metrics, params config.StageParam) (Transformer, error) {
 	var needToInitLocationDB = false
 	var needToInitKubeData = false
 	var needToInitNetworkServices = false
+	var needToInitReverseDNS = false
+	var needToInitASNDB = false
+	var needToInitMacVendors = false
+	var needToInitThreatLists = false
+	var needToInitPolicies = false
 
 	jsonNetworkTransform := api.TransformNetwork{}
 	if params.Transform != nil && params.Transform.Network != nil {
@@ -199,31 +289,123 @@ func NewTransformNetwork(params config.StageParam) (Transformer, error) {
 			needToInitLocationDB = true
 		case api.OpAddKubernetes:
 			needToInitKubeData = true
-		case api.OpAddService:
+		case api.OpAddNetworkPolicy:
+			needToInitKubeData = true
+			needToInitPolicies = true
+		case api.OpAddService, api.OpAddServerPort, api.OpDecodeProtocol:
 			needToInitNetworkServices = true
+		case api.OpDecodeTCPFlags:
+			if !validateTCPFlagsParameters(rule.Parameters) {
+				return nil, fmt.Errorf("invalid parameters for rule '%s': %q, expected '%s' or '%s'",
+					api.OpDecodeTCPFlags, rule.Parameters, tcpFlagsAsString, tcpFlagsAsList)
+			}
+		case api.OpAddReverseDNS:
+			needToInitReverseDNS = true
+		case api.OpAddASN:
+			if jsonNetworkTransform.ASNDBPath == "" {
+				return nil, fmt.Errorf("a rule '%s' was found, but there is no ASN database configured", api.OpAddASN)
+			}
+			needToInitASNDB = true
+		case api.OpAddMacVendor:
+			if len(jsonNetworkTransform.MacVendorFiles) == 0 {
+				return nil, fmt.Errorf("a rule '%s' was found, but there are no MAC vendor files configured", api.OpAddMacVendor)
+			}
+			needToInitMacVendors = true
+		case api.OpAddThreatIntel:
+			if err := validateThreatIntelConfig(jsonNetworkTransform.ThreatIntel); err != nil {
+				return nil, err
+			}
+			needToInitThreatLists = true
 		case api.OpReinterpretDirection:
 			if err := validateReinterpretDirectionConfig(&jsonNetworkTransform.DirectionInfo); err != nil {
 				return nil, err
 			}
+		case api.OpInferDirection:
+			if err := validateInferDirectionConfig(&jsonNetworkTransform); err != nil {
+				return nil, err
+			}
+			if jsonNetworkTransform.DirectionInference.SwapToClientServer {
+				needToInitNetworkServices = true
+			}
 		case api.OpAddIPCategory:
-			if len(jsonNetworkTransform.IPCategories) == 0 {
+			if len(jsonNetworkTransform.IPCategories) == 0 && len(jsonNetworkTransform.IPCategoriesFiles) == 0 {
 				return nil, fmt.Errorf("a rule '%s' was found, but there are no IP categories configured", api.OpAddIPCategory)
 			}
+		case api.OpAddSubnetLabel:
+			if len(jsonNetworkTransform.SubnetLabels) == 0 {
+				return nil, fmt.Errorf("a rule '%s' was found, but there are no subnet labels configured", api.OpAddSubnetLabel)
+			}
 		}
 	}
 
+	var locationDB *location.DB
+	if jsonNetworkTransform.LocationDBChecksumPath != "" && jsonNetworkTransform.LocationDBPath == "" {
+		return nil, fmt.Errorf("locationDBChecksumPath requires locationDBPath to be set")
+	}
 	if needToInitLocationDB {
-		err := location.InitLocationDB()
-		if err != nil {
-			log.Debugf("location.InitLocationDB error: %v", err)
+		if jsonNetworkTransform.LocationDBPath != "" {
+			var err error
+			locationDB, err = location.OpenDB(jsonNetworkTransform.LocationDBPath, jsonNetworkTransform.LocationDBChecksumPath)
+			if err != nil {
+				return nil, err
+			}
+			watched := []string{jsonNetworkTransform.LocationDBPath}
+			if jsonNetworkTransform.LocationDBChecksumPath != "" {
+				watched = append(watched, jsonNetworkTransform.LocationDBChecksumPath)
+			}
+			utils.WatchFiles(watched, jsonNetworkTransform.GetReloadPeriod(), func() {
+				if err := locationDB.Reload(); err != nil {
+					log.WithError(err).Error("can't reload location database. Keeping previous one")
+					return
+				}
+				log.WithField("path", jsonNetworkTransform.LocationDBPath).Info("location database reloaded")
+			})
+		} else if err := location.InitLocationDB(); err != nil {
+			return nil, fmt.Errorf("can't initialize location database: %w", err)
 		}
 	}
 
+	var kubeClusters *kubernetes.Clusters
+	var kubeInventory *kubernetes.InventoryData
 	if needToInitKubeData {
-		err := kubernetes.Data.InitFromConfig(jsonNetworkTransform.KubeConfigPath)
+		if jsonNetworkTransform.Kubernetes != nil && len(jsonNetworkTransform.Kubernetes.Clusters) > 0 {
+			var err error
+			kubeClusters, err = kubernetes.NewClusters(jsonNetworkTransform.Kubernetes, jsonNetworkTransform.GetReloadPeriod())
+			if err != nil {
+				return nil, err
+			}
+		} else if jsonNetworkTransform.Kubernetes.GetInventoryFile() != "" {
+			kubeInventory = kubernetes.NewInventoryData(jsonNetworkTransform.GetReloadPeriod())
+			if err := kubeInventory.InitFromConfig(jsonNetworkTransform.KubeConfigPath, jsonNetworkTransform.Kubernetes); err != nil {
+				return nil, err
+			}
+		} else if err := kubernetes.Data.InitFromConfig(jsonNetworkTransform.KubeConfigPath, jsonNetworkTransform.Kubernetes); err != nil {
+			return nil, err
+		}
+	}
+
+	var policies *kubernetes.PolicyEvaluator
+	if needToInitPolicies && kubeClusters != nil {
+		if err := kubeClusters.InitPolicies(); err != nil {
+			return nil, err
+		}
+	} else if needToInitPolicies {
+		// NetworkPolicies are always read from the live cluster, even when its Pods are read from an inventory file
+		var pods kubernetes.PodSource = kubernetes.Data
+		if kubeInventory != nil {
+			pods = kubeInventory
+		}
+		kubeConfig, err := kubernetes.LoadConfig(jsonNetworkTransform.KubeConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		kubeClient, err := k8sclient.NewForConfig(kubeConfig)
 		if err != nil {
 			return nil, err
 		}
+		if policies, err = kubernetes.NewPolicyEvaluator(kubeClient, pods); err != nil {
+			return nil, err
+		}
 	}
 
 	var servicesDB *netdb.ServiceNames
@@ -244,30 +426,104 @@ func NewTransformNetwork(params config.StageParam) (Transformer, error) {
 		if err != nil {
 			return nil, err
 		}
+		if err := loadServiceOverrides(servicesDB, &jsonNetworkTransform); err != nil {
+			return nil, err
+		}
 	}
 
-	var subnetCats []subnetCategory
-	for _, category := range jsonNetworkTransform.IPCategories {
-		var cidrs []*net.IPNet
-		for _, cidr := range category.CIDRs {
-			_, parsed, err := net.ParseCIDR(cidr)
-			if err != nil {
-				return nil, fmt.Errorf("category %s: fail to parse CIDR, %w", category.Name, err)
+	subnetCats, err := buildIPCategories(jsonNetworkTransform.IPCategories, jsonNetworkTransform.IPCategoriesFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolver *rdns.Resolver
+	if needToInitReverseDNS {
+		resolver, err = rdns.NewResolver(opMetrics, params.Name, jsonNetworkTransform.ReverseDNS)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var asnDB *asn.DB
+	if needToInitASNDB {
+		asnDB, err = asn.OpenDB(jsonNetworkTransform.ASNDBPath)
+		if err != nil {
+			return nil, err
+		}
+		utils.WatchFiles([]string{jsonNetworkTransform.ASNDBPath}, jsonNetworkTransform.GetReloadPeriod(), func() {
+			if err := asnDB.Reload(); err != nil {
+				log.WithError(err).Error("can't reload ASN database. Keeping previous one")
+				return
 			}
-			cidrs = append(cidrs, parsed)
+			log.WithField("path", jsonNetworkTransform.ASNDBPath).Info("ASN database reloaded")
+		})
+	}
+
+	var macVendors *oui.DB
+	if needToInitMacVendors {
+		macVendors, err = oui.OpenDB(jsonNetworkTransform.MacVendorFiles)
+		if err != nil {
+			return nil, err
 		}
-		if len(cidrs) > 0 {
-			subnetCats = append(subnetCats, subnetCategory{name: category.Name, cidrs: cidrs})
+		utils.WatchFiles(jsonNetworkTransform.MacVendorFiles, jsonNetworkTransform.GetReloadPeriod(), func() {
+			if err := macVendors.Reload(); err != nil {
+				log.WithError(err).Error("can't reload MAC vendor files. Keeping previous ones")
+				return
+			}
+			log.WithField("paths", jsonNetworkTransform.MacVendorFiles).Info("MAC vendor files reloaded")
+		})
+	}
+
+	var threatLists []threatList
+	if needToInitThreatLists {
+		threatLists, err = openThreatLists(opMetrics, params.Name, &jsonNetworkTransform)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return &Network{
+	subnetLabels, err := buildSubnetLabels(jsonNetworkTransform.SubnetLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	network := &Network{
 		TransformNetwork: api.TransformNetwork{
-			Rules:         jsonNetworkTransform.Rules,
-			DirectionInfo: jsonNetworkTransform.DirectionInfo,
+			Rules:              jsonNetworkTransform.Rules,
+			DirectionInfo:      jsonNetworkTransform.DirectionInfo,
+			DirectionInference: jsonNetworkTransform.DirectionInference,
+			ServerPortInfo:     jsonNetworkTransform.ServerPortInfo,
+			ICMPInfo:           jsonNetworkTransform.ICMPInfo,
+			CommunityIDInfo:    jsonNetworkTransform.CommunityIDInfo,
+			SamplingInfo:       jsonNetworkTransform.SamplingInfo,
+			ThreatIntel:        jsonNetworkTransform.ThreatIntel,
+			NetworkPolicyInfo:  jsonNetworkTransform.NetworkPolicyInfo,
+			Kubernetes:         jsonNetworkTransform.Kubernetes,
+			IPCategories:       jsonNetworkTransform.IPCategories,
+			IPCategoriesFiles:  jsonNetworkTransform.IPCategoriesFiles,
 		},
-		svcNames:   servicesDB,
-		categories: subnetCats,
-		ipCatCache: utils.NewQuietExpiringTimedCache(2 * time.Minute),
-	}, nil
+		svcNames:      servicesDB,
+		ipCatCache:    utils.NewQuietExpiringTimedCache(2 * time.Minute),
+		subnetLabels:  subnetLabels,
+		rdns:          resolver,
+		asnDB:         asnDB,
+		macVendors:    macVendors,
+		threatLists:   threatLists,
+		locationDB:    locationDB,
+		kubeClusters:  kubeClusters,
+		kubeInventory: kubeInventory,
+		policies:      policies,
+	}
+	if needToInitKubeData {
+		network.kubeHistoryLookups = opMetrics.NewCounter(&kubeHistoryLookupsCounter, params.Name)
+	}
+	network.categories = subnetCats
+	if err := network.compileRules(opMetrics, params.Name); err != nil {
+		return nil, err
+	}
+	if len(jsonNetworkTransform.IPCategoriesFiles) > 0 {
+		utils.WatchFiles(jsonNetworkTransform.IPCategoriesFiles, jsonNetworkTransform.GetReloadPeriod(), network.reloadIPCategories)
+	}
+
+	return network, nil
 }
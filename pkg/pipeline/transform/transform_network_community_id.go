@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"net"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/netdb"
+)
+
+// Community ID v1, as specified in https://github.com/corelight/community-id-spec. SHA-1 is mandated by the
+// specification, for interoperability rather than security.
+
+const (
+	communityIDPrefix        = "1:"
+	defaultCommunityIDOutput = "CommunityID"
+	protocolTCP              = 6
+	protocolUDP              = 17
+	protocolSCTP             = 132
+)
+
+// icmpCounterparts map the ICMP types of request/response messages to each other. Messages with these types
+// are hashed as bidirectional flows, with the counterpart type in place of the destination port, while other
+// types are one-way, with the code in place of the destination port.
+var (
+	icmpCounterparts = map[int]int{
+		8: 0, 0: 8, // echo
+		13: 14, 14: 13, // timestamp
+		15: 16, 16: 15, // information
+		10: 9, 9: 10, // router solicitation/advertisement
+		17: 18, 18: 17, // address mask
+	}
+	icmpv6Counterparts = map[int]int{
+		128: 129, 129: 128, // echo
+		133: 134, 134: 133, // router solicitation/advertisement
+		135: 136, 136: 135, // neighbor solicitation/advertisement
+		130: 131, 131: 130, // multicast listener query/report
+		139: 140, 140: 139, // node information query/response
+		144: 145, 145: 144, // home agent address discovery request/reply
+	}
+)
+
+// addCommunityID sets the Community ID of the flow. Flows with missing or invalid addresses or protocol
+// are ignored.
+func addCommunityID(output config.GenericMap, rule *api.NetworkTransformRule, info *api.NetworkTransformCommunityIDInfo) {
+	srcIP := parseFlowIP(output[info.GetSrcIPField()])
+	dstIP := parseFlowIP(output[info.GetDstIPField()])
+	if srcIP == nil || dstIP == nil || len(srcIP) != len(dstIP) {
+		return
+	}
+	protocol, ok := intField(output, info.GetProtocolField())
+	if !ok || protocol > 255 {
+		return
+	}
+	var srcPort, dstPort int
+	oneWay := false
+	switch protocol {
+	case protocolTCP, protocolUDP, protocolSCTP:
+		srcPort, _ = intField(output, info.GetSrcPortField())
+		dstPort, _ = intField(output, info.GetDstPortField())
+	case netdb.ProtocolICMP, netdb.ProtocolICMPv6:
+		counterparts := icmpCounterparts
+		if protocol == netdb.ProtocolICMPv6 {
+			counterparts = icmpv6Counterparts
+		}
+		srcPort, _ = intField(output, info.GetICMPTypeField())
+		if counterpart, ok := counterparts[srcPort]; ok {
+			dstPort = counterpart
+		} else {
+			dstPort, _ = intField(output, info.GetICMPCodeField())
+			oneWay = true
+		}
+	}
+	outputField := rule.Output
+	if outputField == "" {
+		outputField = defaultCommunityIDOutput
+	}
+	output[outputField] = communityID(info.GetSeed(), srcIP, dstIP, uint8(protocol), uint16(srcPort), uint16(dstPort), oneWay)
+}
+
+// parseFlowIP returns the 4-byte form of IPv4 addresses, so that both addresses of a flow have the same length
+func parseFlowIP(value interface{}) net.IP {
+	str, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	ip := net.ParseIP(str)
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return ipv4
+	}
+	return ip
+}
+
+// communityID hashes the flow tuple ordered from the lower endpoint, so that both directions get the same
+// Community ID, unless the flow is one-way. Ports are only hashed for the protocols having ports (including
+// the ICMP equivalents).
+func communityID(seed uint16, srcIP, dstIP net.IP, protocol uint8, srcPort, dstPort uint16, oneWay bool) string {
+	if !oneWay {
+		if cmp := bytes.Compare(srcIP, dstIP); cmp > 0 || (cmp == 0 && srcPort > dstPort) {
+			srcIP, dstIP = dstIP, srcIP
+			srcPort, dstPort = dstPort, srcPort
+		}
+	}
+	hash := sha1.New()
+	_ = binary.Write(hash, binary.BigEndian, seed)
+	hash.Write(srcIP)
+	hash.Write(dstIP)
+	// the protocol is followed by a padding byte
+	hash.Write([]byte{protocol, 0})
+	switch protocol {
+	case protocolTCP, protocolUDP, protocolSCTP, netdb.ProtocolICMP, netdb.ProtocolICMPv6:
+		_ = binary.Write(hash, binary.BigEndian, srcPort)
+		_ = binary.Write(hash, binary.BigEndian, dstPort)
+	}
+	return communityIDPrefix + base64.StdEncoding.EncodeToString(hash.Sum(nil))
+}
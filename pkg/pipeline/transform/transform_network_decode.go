@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"strings"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/netdb"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/utils"
+)
+
+const (
+	tcpFlagsAsString = "string"
+	tcpFlagsAsList   = "list"
+)
+
+func validateTCPFlagsParameters(parameters string) bool {
+	return parameters == "" || parameters == tcpFlagsAsString || parameters == tcpFlagsAsList
+}
+
+func decodeProtocol(output config.GenericMap, rule *api.NetworkTransformRule, svcNames *netdb.ServiceNames) {
+	protocol, ok := intField(output, rule.Input)
+	if !ok {
+		return
+	}
+	if name := svcNames.ProtocolName(protocol); name != "" {
+		output[rule.Output] = name
+	}
+}
+
+func decodeEtherType(output config.GenericMap, rule *api.NetworkTransformRule) {
+	etherType, ok := intField(output, rule.Input)
+	if !ok {
+		return
+	}
+	if name := netdb.EtherTypeName(etherType); name != "" {
+		output[rule.Output] = name
+	}
+}
+
+func decodeTCPFlags(output config.GenericMap, rule *api.NetworkTransformRule) {
+	bitmask, ok := intField(output, rule.Input)
+	if !ok {
+		return
+	}
+	flags := netdb.TCPFlags(bitmask)
+	if rule.Parameters == tcpFlagsAsList {
+		if flags == nil {
+			flags = []string{}
+		}
+		output[rule.Output] = flags
+	} else {
+		output[rule.Output] = strings.Join(flags, ",")
+	}
+}
+
+// decodeICMP sets the ICMP type and code names. Entries from other protocols are ignored, since
+// their type and code fields, if any, are meaningless.
+func decodeICMP(output config.GenericMap, prefix string, info *api.NetworkTransformICMPInfo) {
+	protocol, ok := icmpProtocol(output[info.GetProtocolField()])
+	if !ok {
+		return
+	}
+	icmpType, ok := intField(output, info.GetTypeField())
+	if !ok {
+		return
+	}
+	icmpCode, _ := intField(output, info.GetCodeField())
+	typeName, codeName := netdb.ICMPNames(protocol, icmpType, icmpCode)
+	if typeName != "" {
+		output[prefix+"TypeName"] = typeName
+	}
+	if codeName != "" {
+		output[prefix+"CodeName"] = codeName
+	}
+}
+
+// icmpProtocol accepts either protocol numbers or names, as found in the protocols file
+func icmpProtocol(value interface{}) (int, bool) {
+	if name, ok := value.(string); ok {
+		switch strings.ToLower(name) {
+		case "icmp":
+			return netdb.ProtocolICMP, true
+		case "ipv6-icmp", "icmpv6":
+			return netdb.ProtocolICMPv6, true
+		}
+	}
+	if value == nil {
+		return 0, false
+	}
+	protocol, err := utils.ConvertToFloat64(value)
+	if err != nil || (int(protocol) != netdb.ProtocolICMP && int(protocol) != netdb.ProtocolICMPv6) {
+		return 0, false
+	}
+	return int(protocol), true
+}
+
+func intField(output config.GenericMap, field string) (int, bool) {
+	value, ok := output[field]
+	if !ok || value == nil {
+		return 0, false
+	}
+	number, err := utils.ConvertToFloat64(value)
+	if err != nil || number < 0 {
+		return 0, false
+	}
+	return int(number), true
+}
@@ -1,7 +1,25 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
 package transform
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/netobserv/flowlogs-pipeline/pkg/api"
 	"github.com/netobserv/flowlogs-pipeline/pkg/config"
@@ -12,6 +30,17 @@ const (
 	egress  = 1
 )
 
+// directions inferred from the internal networks
+const (
+	directionIngress  = "ingress"
+	directionEgress   = "egress"
+	directionInternal = "internal"
+	directionTransit  = "transit"
+	directionExternal = "external"
+
+	defaultInferredDirectionOutput = "Direction"
+)
+
 func validateReinterpretDirectionConfig(info *api.NetworkTransformDirectionInfo) error {
 	if info.FlowDirectionField == "" {
 		return fmt.Errorf("invalid config for transform.Network rule %s: missing FlowDirectionField", api.OpReinterpretDirection)
@@ -59,3 +88,97 @@ func reinterpretDirection(output config.GenericMap, info *api.NetworkTransformDi
 		}
 	}
 }
+
+func validateInferDirectionConfig(tn *api.TransformNetwork) error {
+	if tn.DirectionInference == nil || len(tn.DirectionInference.InternalCategories) == 0 {
+		return fmt.Errorf("invalid config for transform.Network rule %s: missing internalCategories", api.OpInferDirection)
+	}
+	if len(tn.IPCategories) == 0 && len(tn.IPCategoriesFiles) == 0 {
+		return fmt.Errorf("invalid config for transform.Network rule %s: there are no IP categories configured", api.OpInferDirection)
+	}
+	if tn.DirectionInference.GetSrcPrefix() == tn.DirectionInference.GetDstPrefix() {
+		return fmt.Errorf("invalid config for transform.Network rule %s: srcPrefix and dstPrefix must differ", api.OpInferDirection)
+	}
+	return nil
+}
+
+// compileInferDirection sets the direction of the flow from the IP categories of its sides, as seen from the
+// internal networks. When neither side is internal, flows between categorized networks are in transit, while other
+// flows are external. With swapToClientServer, the source and destination are first swapped when the source is the
+// server, so that the direction is the one of the connection.
+func (n *Network) compileInferDirection(rule *api.NetworkTransformRule) func(config.GenericMap) {
+	info := n.DirectionInference
+	outputField := rule.Output
+	if outputField == "" {
+		outputField = defaultInferredDirectionOutput
+	}
+	swappedField := outputField + "_Swapped"
+	serverFields := newServerPortFields(n.ServerPortInfo)
+	srcPrefix, dstPrefix := info.GetSrcPrefix(), info.GetDstPrefix()
+	srcIPField, dstIPField := info.GetSrcIPField(), info.GetDstIPField()
+	return func(output config.GenericMap) {
+		if info.SwapToClientServer {
+			if _, _, srcIsServer, ok := serverSide(output, serverFields, n.svcNames); ok && srcIsServer {
+				swapSrcDst(output, srcPrefix, dstPrefix)
+				output[swappedField] = true
+			}
+		}
+		srcIP, _ := output[srcIPField].(string)
+		dstIP, _ := output[dstIPField].(string)
+		if srcIP == "" || dstIP == "" {
+			return
+		}
+		srcCat, dstCat := n.categorize(srcIP), n.categorize(dstIP)
+		srcInternal, dstInternal := isInternal(info, srcCat), isInternal(info, dstCat)
+		switch {
+		case srcInternal && dstInternal:
+			output[outputField] = directionInternal
+		case srcInternal:
+			output[outputField] = directionEgress
+		case dstInternal:
+			output[outputField] = directionIngress
+		case srcCat != "" && dstCat != "":
+			output[outputField] = directionTransit
+		default:
+			output[outputField] = directionExternal
+		}
+	}
+}
+
+func isInternal(info *api.NetworkTransformDirectionInference, category string) bool {
+	if category == "" {
+		return false
+	}
+	for _, internal := range info.InternalCategories {
+		if category == internal {
+			return true
+		}
+	}
+	return false
+}
+
+// swapSrcDst swaps the values of the source and destination fields having the same suffix. Fields without
+// counterpart are renamed.
+func swapSrcDst(output config.GenericMap, srcPrefix, dstPrefix string) {
+	suffixes := map[string]struct{}{}
+	for key := range output {
+		if strings.HasPrefix(key, srcPrefix) {
+			suffixes[strings.TrimPrefix(key, srcPrefix)] = struct{}{}
+		} else if strings.HasPrefix(key, dstPrefix) {
+			suffixes[strings.TrimPrefix(key, dstPrefix)] = struct{}{}
+		}
+	}
+	for suffix := range suffixes {
+		srcKey, dstKey := srcPrefix+suffix, dstPrefix+suffix
+		srcValue, srcOk := output[srcKey]
+		dstValue, dstOk := output[dstKey]
+		delete(output, srcKey)
+		delete(output, dstKey)
+		if srcOk {
+			output[dstKey] = srcValue
+		}
+		if dstOk {
+			output[srcKey] = dstValue
+		}
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// buildIPCategories creates a prefix tree from the inline categories followed by the categories
+// read from files. When the same CIDR is declared more than once, the first declaration wins.
+func buildIPCategories(inline []api.NetworkTransformIPCategory, files []string) (*utils.PrefixTree, error) {
+	categories := append([]api.NetworkTransformIPCategory{}, inline...)
+	for _, file := range files {
+		fromFile, err := readIPCategoriesFile(file)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, fromFile...)
+	}
+	tree := utils.NewPrefixTree()
+	// inserting in reverse order so that first declarations override the latter
+	for i := len(categories) - 1; i >= 0; i-- {
+		category := categories[i]
+		for _, cidr := range category.CIDRs {
+			_, parsed, err := net.ParseCIDR(strings.TrimSpace(cidr))
+			if err != nil {
+				return nil, fmt.Errorf("category %s: fail to parse CIDR, %w", category.Name, err)
+			}
+			tree.InsertCIDR(parsed, category.Name)
+		}
+	}
+	return tree, nil
+}
+
+// readIPCategoriesFile reads IP categories from a CSV file (with .csv extension) or from a YAML/JSON
+// file (any other extension). YAML files must contain a list of categories, with the same format
+// as the ipCategories configuration. CSV files contain one "cidr,name" record per line; lines
+// starting with '#' are ignored, as well as a first "cidr,name" header line if present.
+func readIPCategoriesFile(path string) ([]api.NetworkTransformIPCategory, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening IP categories file %q: %w", path, err)
+	}
+	defer f.Close()
+	var categories []api.NetworkTransformIPCategory
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		categories, err = parseIPCategoriesCSV(f)
+	} else {
+		categories, err = parseIPCategoriesYAML(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading IP categories file %q: %w", path, err)
+	}
+	return categories, nil
+}
+
+func parseIPCategoriesYAML(in io.Reader) ([]api.NetworkTransformIPCategory, error) {
+	content, err := io.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+	var categories []api.NetworkTransformIPCategory
+	if err := yaml.UnmarshalStrict(content, &categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func parseIPCategoriesCSV(in io.Reader) ([]api.NetworkTransformIPCategory, error) {
+	reader := csv.NewReader(in)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > 0 && strings.EqualFold(records[0][0], "cidr") {
+		records = records[1:]
+	}
+	// keeping the order of first appearance of each category
+	var categories []api.NetworkTransformIPCategory
+	indexes := map[string]int{}
+	for _, record := range records {
+		cidr, name := record[0], strings.TrimSpace(record[1])
+		idx, ok := indexes[name]
+		if !ok {
+			idx = len(categories)
+			indexes[name] = idx
+			categories = append(categories, api.NetworkTransformIPCategory{Name: name})
+		}
+		categories[idx].CIDRs = append(categories[idx].CIDRs, cidr)
+	}
+	return categories, nil
+}
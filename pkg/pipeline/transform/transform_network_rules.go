@@ -0,0 +1,408 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+
+	"github.com/Knetic/govaluate"
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/netobserv/flowlogs-pipeline/pkg/operational"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/location"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/oui"
+	"github.com/sirupsen/logrus"
+)
+
+// networkRule is a rule compiled once, when the stage is created: its parameters are parsed and its output
+// field names are built, so that applying it to an entry does as little work as possible
+type networkRule struct {
+	// condition is nil when the rule has no `if` predicate
+	condition *ruleCondition
+	// apply updates the entry in place
+	apply func(entry config.GenericMap)
+}
+
+// compileRules compiles the rules of the network transform, which must be set up beforehand, since the rules
+// use its databases and settings
+func (n *Network) compileRules(opMetrics *operational.Metrics, stage string) error {
+	rules := make([]networkRule, 0, len(n.Rules))
+	for i := range n.Rules {
+		rule := &n.Rules[i]
+		apply, err := n.compileRule(rule)
+		if err != nil {
+			return err
+		}
+		compiled := networkRule{apply: apply}
+		if rule.If != "" {
+			if compiled.condition, err = newRuleCondition(opMetrics, stage, i, rule.If); err != nil {
+				return err
+			}
+		}
+		rules = append(rules, compiled)
+	}
+	n.rules = rules
+	return nil
+}
+
+func (n *Network) compileRule(rule *api.NetworkTransformRule) (func(config.GenericMap), error) {
+	switch rule.Type {
+	case api.OpAddRegexIf:
+		return compileAddRegexIf(rule)
+	case api.OpAddIf:
+		return compileAddIf(rule)
+	case api.OpAddSubnet:
+		return compileAddSubnet(rule)
+	case api.OpAddSubnetLabel:
+		return n.compileAddSubnetLabel(rule), nil
+	case api.OpAddLocation:
+		return n.compileAddLocation(rule), nil
+	case api.OpAddService:
+		return n.compileAddService(rule), nil
+	case api.OpAddServerPort:
+		return compileAddServerPort(rule, n.ServerPortInfo, n.svcNames), nil
+	case api.OpDecodeProtocol:
+		return func(entry config.GenericMap) { decodeProtocol(entry, rule, n.svcNames) }, nil
+	case api.OpDecodeEtherType:
+		return func(entry config.GenericMap) { decodeEtherType(entry, rule) }, nil
+	case api.OpDecodeTCPFlags:
+		return func(entry config.GenericMap) { decodeTCPFlags(entry, rule) }, nil
+	case api.OpDecodeICMP:
+		prefix := outputPrefix(rule)
+		return func(entry config.GenericMap) { decodeICMP(entry, prefix, n.ICMPInfo) }, nil
+	case api.OpAddThreatIntel:
+		return n.compileAddThreatIntel(rule), nil
+	case api.OpAddCommunityID:
+		return func(entry config.GenericMap) { addCommunityID(entry, rule, n.CommunityIDInfo) }, nil
+	case api.OpScaleSampling:
+		return compileScaleSampling(rule, n.SamplingInfo)
+	case api.OpAddNetworkPolicy:
+		prefix := outputPrefix(rule)
+		return func(entry config.GenericMap) { n.addNetworkPolicy(entry, prefix) }, nil
+	case api.OpAddKubernetes:
+		return n.compileAddKubernetes(rule), nil
+	case api.OpAddReverseDNS:
+		return n.compileAddReverseDNS(rule), nil
+	case api.OpAddASN:
+		return n.compileAddASN(rule), nil
+	case api.OpAddMacVendor:
+		return n.compileAddMacVendor(rule), nil
+	case api.OpReinterpretDirection:
+		return func(entry config.GenericMap) { reinterpretDirection(entry, &n.DirectionInfo) }, nil
+	case api.OpInferDirection:
+		return n.compileInferDirection(rule), nil
+	case api.OpAddIPCategory:
+		return func(entry config.GenericMap) {
+			if strIP, ok := entry[rule.Input].(string); ok {
+				entry[rule.Output] = n.categorize(strIP)
+			}
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown type %s for transform.Network rule: %v", rule.Type, *rule)
+}
+
+// outputPrefix returns the prefix of the output fields of the rules setting several fields, which is the rule
+// output followed by an underscore, or nothing when the rule has no output
+func outputPrefix(rule *api.NetworkTransformRule) string {
+	if rule.Output == "" {
+		return ""
+	}
+	return rule.Output + "_"
+}
+
+// fieldString formats the value as the %v verb does, without allocating for strings and common numbers
+func fieldString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10)
+	}
+	return fmt.Sprint(value)
+}
+
+func compileAddRegexIf(rule *api.NetworkTransformRule) (func(config.GenericMap), error) {
+	re, err := regexp.Compile(rule.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for rule '%s': %w", api.OpAddRegexIf, err)
+	}
+	matchedField := rule.Output + "_Matched"
+	return func(entry config.GenericMap) {
+		if re.MatchString(fieldString(entry[rule.Input])) {
+			entry[rule.Output] = entry[rule.Input]
+			entry[matchedField] = true
+		}
+	}, nil
+}
+
+// addIfParameter provides the value of the input field as the `val` parameter of the add_if expressions
+type addIfParameter struct {
+	value interface{}
+}
+
+func (p *addIfParameter) Get(name string) (interface{}, error) {
+	if name != "val" {
+		return nil, fmt.Errorf("no parameter '%s' found", name)
+	}
+	return p.value, nil
+}
+
+func compileAddIf(rule *api.NetworkTransformRule) (func(config.GenericMap), error) {
+	expression, err := govaluate.NewEvaluableExpression("val " + rule.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for rule '%s': %q: %w", api.OpAddIf, rule.Parameters, err)
+	}
+	evaluateField := rule.Output + "_Evaluate"
+	return func(entry config.GenericMap) {
+		result, err := expression.Eval(&addIfParameter{value: entry[rule.Input]})
+		if err != nil {
+			return
+		}
+		if matched, ok := result.(bool); ok && matched {
+			if rule.Assignee != "" {
+				entry[rule.Output] = rule.Assignee
+			} else {
+				entry[rule.Output] = entry[rule.Input]
+			}
+			entry[evaluateField] = true
+		}
+	}, nil
+}
+
+func compileAddSubnet(rule *api.NetworkTransformRule) (func(config.GenericMap), error) {
+	v4Len, v6Len, err := parsePrefixLengths(rule.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for rule '%s': %w", api.OpAddSubnet, err)
+	}
+	return func(entry config.GenericMap) {
+		subnet, err := subnetOf(net.ParseIP(fieldString(entry[rule.Input])), v4Len, v6Len)
+		if err != nil {
+			log.Warningf("Can't find subnet for IP %v and prefix length %s - err %v", entry[rule.Input], rule.Parameters, err)
+			return
+		}
+		entry[rule.Output] = subnet
+	}, nil
+}
+
+func (n *Network) compileAddSubnetLabel(rule *api.NetworkTransformRule) func(config.GenericMap) {
+	cidrField := rule.Output + "_CIDR"
+	return func(entry config.GenericMap) {
+		if strIP, ok := entry[rule.Input].(string); ok && n.subnetLabels != nil {
+			if label, ok := n.subnetLabels.LookupIP(net.ParseIP(strIP)); ok {
+				entry[rule.Output] = label.(subnetLabel).name
+				entry[cidrField] = label.(subnetLabel).cidr
+			}
+		}
+	}
+}
+
+func (n *Network) compileAddLocation(rule *api.NetworkTransformRule) func(config.GenericMap) {
+	var (
+		countryNameField     = rule.Output + "_CountryName"
+		countryLongNameField = rule.Output + "_CountryLongName"
+		regionNameField      = rule.Output + "_RegionName"
+		cityNameField        = rule.Output + "_CityName"
+		latitudeField        = rule.Output + "_Latitude"
+		longitudeField       = rule.Output + "_Longitude"
+	)
+	return func(entry config.GenericMap) {
+		var locationInfo *location.Info
+		var err error
+		if n.locationDB != nil {
+			locationInfo, err = n.locationDB.GetLocation(fieldString(entry[rule.Input]))
+		} else {
+			err, locationInfo = location.GetLocation(fieldString(entry[rule.Input]))
+		}
+		if err != nil {
+			log.Warningf("Can't find location for IP %v err %v", entry[rule.Input], err)
+			return
+		}
+		entry[countryNameField] = locationInfo.CountryName
+		entry[countryLongNameField] = locationInfo.CountryLongName
+		entry[regionNameField] = locationInfo.RegionName
+		entry[cityNameField] = locationInfo.CityName
+		entry[latitudeField] = locationInfo.Latitude
+		entry[longitudeField] = locationInfo.Longitude
+	}
+}
+
+func (n *Network) compileAddService(rule *api.NetworkTransformRule) func(config.GenericMap) {
+	return func(entry config.GenericMap) {
+		protocol := fieldString(entry[rule.Parameters])
+		portNumber, err := strconv.Atoi(fieldString(entry[rule.Input]))
+		if err != nil {
+			log.Errorf("Can't convert port to int: Port %v - err %v", entry[rule.Input], err)
+			return
+		}
+		var serviceName string
+		protocolAsNumber, err := strconv.Atoi(protocol)
+		if err == nil {
+			// protocol has been submitted as number
+			serviceName = n.svcNames.ByPortAndProtocolNumber(portNumber, protocolAsNumber)
+		} else {
+			// protocol has been submitted as any string
+			serviceName = n.svcNames.ByPortAndProtocolName(portNumber, protocol)
+		}
+		if serviceName == "" && err != nil {
+			log.Debugf("Can't find service name for Port %v and protocol %v - err %v", entry[rule.Input], protocol, err)
+			return
+		}
+		entry[rule.Output] = serviceName
+	}
+}
+
+func (n *Network) compileAddKubernetes(rule *api.NetworkTransformRule) func(config.GenericMap) {
+	var (
+		clusterField            = rule.Output + "_Cluster"
+		namespaceField          = rule.Output + "_Namespace"
+		nameField               = rule.Output + "_Name"
+		networkField            = rule.Output + "_Network"
+		typeField               = rule.Output + "_Type"
+		ownerNameField          = rule.Output + "_OwnerName"
+		ownerTypeField          = rule.Output + "_OwnerType"
+		immediateOwnerNameField = rule.Output + "_ImmediateOwnerName"
+		immediateOwnerTypeField = rule.Output + "_ImmediateOwnerType"
+		hostIPField             = rule.Output + "_HostIP"
+		hostNameField           = rule.Output + "_HostName"
+		labelPrefix             = rule.Parameters + "_"
+		namespaceLabelPrefix    = rule.Output + "_NamespaceLabel_"
+		annotationPrefix        = rule.Output + "_Annotation_"
+		nodeLabelPrefix         = rule.Output + "_NodeLabel_"
+		serviceNameField        = outputPrefix(rule) + "ServiceName"
+		endpointsField          = outputPrefix(rule) + "Endpoints"
+	)
+	withServiceEndpoints := n.Kubernetes != nil && n.Kubernetes.ServiceEndpoints != nil
+	// the immediate owner is only relevant when the owner depth is configured, and only set when it differs from
+	// the top-level owner, not to change the output of existing deployments
+	withImmediateOwner := n.Kubernetes.GetOwnerDepth() > 0
+	return func(entry config.GenericMap) {
+		kubeInfo, cluster, err := n.getKubeInfo(entry, fieldString(entry[rule.Input]))
+		if err != nil {
+			logrus.WithError(err).Tracef("can't find kubernetes info for IP %v", entry[rule.Input])
+			return
+		}
+		if cluster != "" {
+			entry[clusterField] = cluster
+		}
+		// NETOBSERV-666: avoid putting empty namespaces or Loki aggregation queries will
+		// differentiate between empty and nil namespaces.
+		if kubeInfo.Namespace != "" {
+			entry[namespaceField] = kubeInfo.Namespace
+		}
+		entry[nameField] = kubeInfo.Name
+		if kubeInfo.Network != "" {
+			entry[networkField] = kubeInfo.Network
+		}
+		entry[typeField] = kubeInfo.Type
+		entry[ownerNameField] = kubeInfo.Owner.Name
+		entry[ownerTypeField] = kubeInfo.Owner.Type
+		if withImmediateOwner && kubeInfo.ImmediateOwner != kubeInfo.Owner {
+			entry[immediateOwnerNameField] = kubeInfo.ImmediateOwner.Name
+			entry[immediateOwnerTypeField] = kubeInfo.ImmediateOwner.Type
+		}
+		if rule.Parameters != "" {
+			for labelKey, labelValue := range kubeInfo.Labels {
+				entry[labelPrefix+labelKey] = labelValue
+			}
+		}
+		for labelKey, labelValue := range kubeInfo.NamespaceLabels {
+			entry[namespaceLabelPrefix+labelKey] = labelValue
+		}
+		for annotationKey, annotationValue := range kubeInfo.Annotations {
+			entry[annotationPrefix+annotationKey] = annotationValue
+		}
+		for labelKey, labelValue := range kubeInfo.NodeLabels {
+			entry[nodeLabelPrefix+labelKey] = labelValue
+		}
+		if kubeInfo.HostIP != "" {
+			entry[hostIPField] = kubeInfo.HostIP
+			if kubeInfo.HostName != "" {
+				entry[hostNameField] = kubeInfo.HostName
+			}
+		}
+		if withServiceEndpoints {
+			n.addServiceEndpoints(entry, rule.Input, serviceNameField, endpointsField)
+		}
+	}
+}
+
+func (n *Network) compileAddReverseDNS(rule *api.NetworkTransformRule) func(config.GenericMap) {
+	return func(entry config.GenericMap) {
+		if strIP, ok := entry[rule.Input].(string); ok && strIP != "" {
+			if hostName, ok := n.rdns.HostName(strIP); ok {
+				entry[rule.Output] = hostName
+			}
+		}
+	}
+}
+
+func (n *Network) compileAddASN(rule *api.NetworkTransformRule) func(config.GenericMap) {
+	prefix := outputPrefix(rule)
+	asnField := prefix + "ASN"
+	asOrgField := prefix + "ASOrg"
+	return func(entry config.GenericMap) {
+		strIP, ok := entry[rule.Input].(string)
+		if !ok {
+			return
+		}
+		ip := net.ParseIP(strIP)
+		if ip == nil {
+			return
+		}
+		asnInfo, err := n.asnDB.Lookup(ip)
+		if err != nil {
+			log.WithError(err).Debugf("can't find ASN for IP %v", strIP)
+			return
+		}
+		if asnInfo != nil {
+			entry[asnField] = asnInfo.ASN
+			entry[asOrgField] = asnInfo.Organization
+		}
+	}
+}
+
+func (n *Network) compileAddMacVendor(rule *api.NetworkTransformRule) func(config.GenericMap) {
+	prefix := outputPrefix(rule)
+	vendorField := prefix + "MacVendor"
+	locallyAdministeredField := prefix + "MacLocallyAdministered"
+	multicastField := prefix + "MacMulticast"
+	return func(entry config.GenericMap) {
+		strMac, ok := entry[rule.Input].(string)
+		if !ok {
+			return
+		}
+		mac, err := net.ParseMAC(strMac)
+		if err != nil {
+			return
+		}
+		if vendor, ok := n.macVendors.Lookup(mac); ok {
+			entry[vendorField] = vendor
+		}
+		entry[locallyAdministeredField] = oui.IsLocallyAdministered(mac)
+		entry[multicastField] = oui.IsMulticast(mac)
+	}
+}
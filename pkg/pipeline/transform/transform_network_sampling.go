@@ -0,0 +1,142 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/utils"
+)
+
+const (
+	defaultSamplingOutput = "SamplingUpscaled"
+	rawCounterSuffix      = "_Raw"
+)
+
+// compileScaleSampling builds the scale_sampling rule. The counters are multiplied by the rate of the flow or,
+// when it is missing, 0 (unknown) or invalid (negative), by the rate of its exporter or the default rate. Records already flagged
+// as upscaled are left unchanged, so that they are never scaled twice, as well as records without known rate.
+func compileScaleSampling(rule *api.NetworkTransformRule, info *api.NetworkTransformSamplingInfo) (func(config.GenericMap), error) {
+	exporters := utils.NewPrefixTree()
+	defaultRate := 0
+	if info != nil {
+		if info.DefaultRate < 0 {
+			return nil, fmt.Errorf("invalid sampling default rate %d: must be positive", info.DefaultRate)
+		}
+		defaultRate = info.DefaultRate
+		for i := range info.Exporters {
+			exporter := &info.Exporters[i]
+			if exporter.Rate <= 0 || len(exporter.CIDRs) == 0 {
+				return nil, fmt.Errorf("invalid exporter sampling %+v: cidrs and a positive rate are required", *exporter)
+			}
+			for _, str := range exporter.CIDRs {
+				cidr, err := parseCIDROrIP(str)
+				if err != nil {
+					return nil, fmt.Errorf("invalid exporter sampling CIDR %q: %w", str, err)
+				}
+				exporters.InsertCIDR(cidr, exporter.Rate)
+			}
+		}
+	}
+	rateField := info.GetRateField()
+	exporterField := info.GetExporterField()
+	fields := info.GetFields()
+	rawFields := make([]string, len(fields))
+	for i, field := range fields {
+		rawFields[i] = field + rawCounterSuffix
+	}
+	outputField := rule.Output
+	if outputField == "" {
+		outputField = defaultSamplingOutput
+	}
+	return func(entry config.GenericMap) {
+		if upscaled, _ := entry[outputField].(bool); upscaled {
+			return
+		}
+		rate, ok := intField(entry, rateField)
+		if !ok || rate <= 0 {
+			if exporterRate, found := exporters.LookupIP(exporterIP(entry[exporterField])); found {
+				rate = exporterRate.(int)
+			} else {
+				rate = defaultRate
+			}
+		}
+		if rate == 0 {
+			return
+		}
+		for i, field := range fields {
+			value, ok := entry[field]
+			if !ok {
+				continue
+			}
+			if scaled, ok := scaleCounter(value, rate); ok {
+				entry[rawFields[i]] = value
+				entry[field] = scaled
+			}
+		}
+		entry[outputField] = true
+	}, nil
+}
+
+// exporterIP reads the exporter IP from its string form, or from its bytes, as provided by the collector ingester
+func exporterIP(value interface{}) net.IP {
+	switch v := value.(type) {
+	case string:
+		return net.ParseIP(v)
+	case []byte:
+		if len(v) == net.IPv4len || len(v) == net.IPv6len {
+			return v
+		}
+	}
+	return nil
+}
+
+// scaleCounter multiplies the counter by the rate, keeping its type, except for the integers smaller than
+// 64 bits, which are widened to avoid overflows
+func scaleCounter(value interface{}, rate int) (interface{}, bool) {
+	switch v := value.(type) {
+	case int:
+		return v * rate, true
+	case int64:
+		return v * int64(rate), true
+	case int32:
+		return int64(v) * int64(rate), true
+	case int16:
+		return int64(v) * int64(rate), true
+	case int8:
+		return int64(v) * int64(rate), true
+	case uint:
+		return uint64(v) * uint64(rate), true
+	case uint64:
+		return v * uint64(rate), true
+	case uint32:
+		return uint64(v) * uint64(rate), true
+	case uint16:
+		return uint64(v) * uint64(rate), true
+	case uint8:
+		return uint64(v) * uint64(rate), true
+	case float64:
+		return v * float64(rate), true
+	case float32:
+		return float64(v) * float64(rate), true
+	}
+	return nil, false
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"strconv"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/netdb"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/utils"
+)
+
+const maxWellKnownPort = 1023
+
+var connectionRecordTypes = map[string]struct{}{
+	api.ConnTrackOutputRecordTypeName("NewConnection"):    {},
+	api.ConnTrackOutputRecordTypeName("UpdateConnection"): {},
+	api.ConnTrackOutputRecordTypeName("EndConnection"):    {},
+}
+
+// serverPortFields are the fields read to find the server side of the flow, resolved from
+// NetworkTransformServerPortInfo when compiling the rules
+type serverPortFields struct {
+	srcPort  string
+	dstPort  string
+	protocol string
+}
+
+func newServerPortFields(info *api.NetworkTransformServerPortInfo) *serverPortFields {
+	return &serverPortFields{
+		srcPort:  info.GetSrcPortField(),
+		dstPort:  info.GetDstPortField(),
+		protocol: info.GetProtocolField(),
+	}
+}
+
+// compileAddServerPort sets the port and service name of the server side of the flow, so that both
+// directions of a connection are labeled the same way
+func compileAddServerPort(rule *api.NetworkTransformRule, info *api.NetworkTransformServerPortInfo, svcNames *netdb.ServiceNames) func(config.GenericMap) {
+	fields := newServerPortFields(info)
+	prefix := outputPrefix(rule)
+	serverPortField, serviceNameField := prefix+"ServerPort", prefix+"ServiceName"
+	return func(output config.GenericMap) {
+		serverPort, serverName, _, ok := serverSide(output, fields, svcNames)
+		if !ok {
+			return
+		}
+		output[serverPortField] = serverPort
+		if serverName != "" {
+			output[serviceNameField] = serverName
+		}
+	}
+}
+
+// serverSide returns the port and service name of the server side of the flow, and whether it is the source
+// side. It fails when any of the ports is missing.
+func serverSide(output config.GenericMap, fields *serverPortFields, svcNames *netdb.ServiceNames) (int, string, bool, bool) {
+	srcPort, srcOk := portField(output, fields.srcPort)
+	dstPort, dstOk := portField(output, fields.dstPort)
+	if !srcOk || !dstOk {
+		return 0, "", false, false
+	}
+	protocol := output[fields.protocol]
+	srcName := serviceName(svcNames, srcPort, protocol)
+	dstName := serviceName(svcNames, dstPort, protocol)
+
+	if !isConnectionRecord(output) {
+		srcScore, dstScore := serverSideScore(srcPort, srcName), serverSideScore(dstPort, dstName)
+		if srcScore > dstScore || (srcScore == dstScore && srcPort < dstPort) {
+			return srcPort, srcName, true, true
+		}
+	}
+	return dstPort, dstName, false, true
+}
+
+// isConnectionRecord returns whether the entry is a connection record from the conntrack stage. Its
+// fields are taken from the first flow of the connection, which is sent from the client (A) to the server (B).
+func isConnectionRecord(output config.GenericMap) bool {
+	recordType, _ := output[api.RecordTypeFieldName].(string)
+	_, ok := connectionRecordTypes[recordType]
+	return ok
+}
+
+// serverSideScore tells how likely a port is to be the listening port: ports with a known service name,
+// from the overrides or the services file, are preferred over other well-known ports. Ties are broken
+// by choosing the lower port, since client ports are usually ephemeral.
+func serverSideScore(port int, svcName string) int {
+	score := 0
+	if svcName != "" {
+		score += 2
+	}
+	if port <= maxWellKnownPort {
+		score++
+	}
+	return score
+}
+
+func portField(output config.GenericMap, field string) (int, bool) {
+	port, ok := intField(output, field)
+	return port, ok && port > 0
+}
+
+// serviceName looks up the service by protocol number, when the protocol is a number or a numeric string,
+// or by protocol name otherwise
+func serviceName(svcNames *netdb.ServiceNames, port int, protocol interface{}) string {
+	switch p := protocol.(type) {
+	case nil:
+		return ""
+	case string:
+		if protocolNum, err := strconv.Atoi(p); err == nil {
+			return svcNames.ByPortAndProtocolNumber(port, protocolNum)
+		}
+		return svcNames.ByPortAndProtocolName(port, p)
+	}
+	number, err := utils.ConvertToFloat64(protocol)
+	if err != nil || number != float64(int(number)) {
+		return ""
+	}
+	return svcNames.ByPortAndProtocolNumber(port, int(number))
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/utils"
+)
+
+type subnetLabel struct {
+	name string
+	cidr string
+}
+
+// parsePrefixLengths reads add_subnet parameters, such as "/24" (applied to both IPv4 and IPv6) or "/24,/64"
+// (IPv4 prefix length, then IPv6 prefix length). As in previous versions, a single length longer than /32 is
+// accepted and only applies to IPv6 addresses: IPv4 addresses are then skipped by subnetOf.
+func parsePrefixLengths(params string) (v4 int, v6 int, err error) {
+	parts := strings.Split(params, ",")
+	if len(parts) > 2 {
+		return 0, 0, fmt.Errorf("too many prefix lengths in %q", params)
+	}
+	if len(parts) == 1 {
+		if v4, err = parsePrefixLength(parts[0], 8*net.IPv6len); err != nil {
+			return 0, 0, err
+		}
+		return v4, v4, nil
+	}
+	if v4, err = parsePrefixLength(parts[0], 8*net.IPv4len); err != nil {
+		return 0, 0, err
+	}
+	if v6, err = parsePrefixLength(parts[1], 8*net.IPv6len); err != nil {
+		return 0, 0, err
+	}
+	return v4, v6, nil
+}
+
+func parsePrefixLength(str string, maxLen int) (int, error) {
+	str = strings.TrimPrefix(strings.TrimSpace(str), "/")
+	l, err := strconv.Atoi(str)
+	if err != nil {
+		return 0, fmt.Errorf("invalid prefix length %q: %w", str, err)
+	}
+	if l < 0 || l > maxLen {
+		return 0, fmt.Errorf("invalid prefix length %d: must be between 0 and %d", l, maxLen)
+	}
+	return l, nil
+}
+
+// subnetOf returns the CIDR notation of the subnet containing ip, using the prefix length
+// that corresponds to its address family
+func subnetOf(ip net.IP, v4Len, v6Len int) (string, error) {
+	if ip == nil {
+		return "", fmt.Errorf("not an IP")
+	}
+	var ipNet net.IPNet
+	if ip4 := ip.To4(); ip4 != nil {
+		if v4Len > 8*net.IPv4len {
+			return "", fmt.Errorf("invalid IPv4 prefix length %d", v4Len)
+		}
+		ipNet.Mask = net.CIDRMask(v4Len, 8*net.IPv4len)
+		ipNet.IP = ip4.Mask(ipNet.Mask)
+	} else {
+		ipNet.Mask = net.CIDRMask(v6Len, 8*net.IPv6len)
+		ipNet.IP = ip.Mask(ipNet.Mask)
+	}
+	return ipNet.String(), nil
+}
+
+func buildSubnetLabels(labels []api.NetworkTransformSubnetLabel) (*utils.PrefixTree, error) {
+	tree := utils.NewPrefixTree()
+	for _, label := range labels {
+		for _, cidr := range label.CIDRs {
+			_, parsed, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("subnet label %s: fail to parse CIDR, %w", label.Name, err)
+			}
+			tree.InsertCIDR(parsed, subnetLabel{name: label.Name, cidr: parsed.String()})
+		}
+	}
+	return tree, nil
+}
@@ -22,17 +22,25 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/netobserv/flowlogs-pipeline/pkg/api"
 	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/netobserv/flowlogs-pipeline/pkg/operational"
 	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/kubernetes"
 	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/location"
 	netdb "github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/netdb"
 	"github.com/netobserv/flowlogs-pipeline/pkg/test"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
+var opMetrics = operational.NewMetrics(&config.MetricsSettings{})
+
 func getMockNetworkTransformRules() api.NetworkTransformRules {
 	return api.NetworkTransformRules{
 		api.NetworkTransformRule{
@@ -119,6 +127,12 @@ func getExpectedOutput() config.GenericMap {
 	}
 }
 
+func getLocationDB(t *testing.T) *location.DB {
+	db, err := location.OpenDB(path.Join("location", "testdata", "IP2LOCATION-DB5-test.BIN"), "")
+	require.NoError(t, err)
+	return db
+}
+
 func getServicesDB(t *testing.T) *netdb.ServiceNames {
 	etcProtos, err := os.Open(path.Join("netdb", "testdata", "etcProtocols.txt"))
 	require.NoError(t, err)
@@ -142,11 +156,10 @@ func Test_Transform(t *testing.T) {
 		TransformNetwork: api.TransformNetwork{
 			Rules: rules,
 		},
-		svcNames: getServicesDB(t),
+		svcNames:   getServicesDB(t),
+		locationDB: getLocationDB(t),
 	}
-
-	err := location.InitLocationDB()
-	require.NoError(t, err)
+	require.NoError(t, networkTransform.compileRules(opMetrics, ""))
 
 	output, ok := networkTransform.Transform(entry)
 	require.True(t, ok)
@@ -166,11 +179,10 @@ func Test_TransformAddSubnetParseCIDRFailure(t *testing.T) {
 		TransformNetwork: api.TransformNetwork{
 			Rules: rules,
 		},
-		svcNames: getServicesDB(t),
+		svcNames:   getServicesDB(t),
+		locationDB: getLocationDB(t),
 	}
-
-	err := location.InitLocationDB()
-	require.NoError(t, err)
+	require.NoError(t, networkTransform.compileRules(opMetrics, ""))
 
 	output, ok := networkTransform.Transform(entry)
 	require.True(t, ok)
@@ -213,7 +225,7 @@ func InitNewTransformNetwork(t *testing.T, configFile string) Transformer {
 	v, cfg := test.InitConfig(t, configFile)
 	require.NotNil(t, v)
 	config := cfg.Parameters[0]
-	newTransform, err := NewTransformNetwork(config)
+	newTransform, err := NewTransformNetwork(opMetrics, config)
 	require.NoError(t, err)
 	return newTransform
 }
@@ -294,6 +306,7 @@ func Test_Transform_AddIfScientificNotation(t *testing.T) {
 		},
 		svcNames: getServicesDB(t),
 	}
+	require.NoError(t, newNetworkTransform.compileRules(opMetrics, ""))
 
 	var entry config.GenericMap
 	entry = config.GenericMap{
@@ -344,6 +357,7 @@ func TestTransform_K8sEmptyNamespace(t *testing.T) {
 			}},
 		},
 	}
+	require.NoError(t, nt.compileRules(opMetrics, ""))
 	// We need to check that, whether it returns NotFound or just an empty namespace,
 	// there is no map entry for that namespace (an empty-valued map entry is not valid)
 	out, _ := nt.Transform(config.GenericMap{
@@ -356,17 +370,198 @@ func TestTransform_K8sEmptyNamespace(t *testing.T) {
 
 type fakeKubeData struct{}
 
-func (d *fakeKubeData) InitFromConfig(_ string) error {
+func (d *fakeKubeData) InitFromConfig(_ string, _ *api.NetworkTransformKubernetes) error {
 	return nil
 }
-func (*fakeKubeData) GetInfo(n string) (*kubernetes.Info, error) {
+func (*fakeKubeData) GetInfo(n string, timestamp time.Time) (*kubernetes.Info, error) {
 	// If found, returns an empty info (empty namespace)
 	if n == "1.2.3.4" {
 		return &kubernetes.Info{}, nil
 	}
+	if n == "5.6.7.8" {
+		return &kubernetes.Info{
+			ObjectMeta:      metav1.ObjectMeta{Name: "pod", Namespace: "ns", Annotations: map[string]string{"team": "network"}},
+			Type:            "Pod",
+			Network:         "ns/macvlan",
+			Owner:           kubernetes.Owner{Name: "backup", Type: "CronJob"},
+			ImmediateOwner:  kubernetes.Owner{Name: "backup-27800000", Type: "Job"},
+			NamespaceLabels: map[string]string{"cost-center": "1234"},
+			NodeLabels:      map[string]string{"topology.kubernetes.io/zone": "us-east-1a"},
+		}, nil
+	}
+	if n == "9.9.9.9" {
+		// the IP was reassigned at 1,000,000 ms
+		if !timestamp.IsZero() && timestamp.Before(time.UnixMilli(1_000_000)) {
+			return &kubernetes.Info{ObjectMeta: metav1.ObjectMeta{Name: "deleted-pod"}, Type: "Pod", FromHistory: true}, nil
+		}
+		return &kubernetes.Info{ObjectMeta: metav1.ObjectMeta{Name: "new-pod"}, Type: "Pod"}, nil
+	}
+	if n == "172.30.0.10" {
+		return &kubernetes.Info{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns"}, Type: "Service"}, nil
+	}
+	return nil, errors.New("notFound")
+}
+
+func (*fakeKubeData) GetServiceEndpoints(ip string, port int, protocol string) (*kubernetes.ServiceEndpoints, error) {
+	if ip == "5.6.7.8" && port == 8080 && protocol == "TCP" {
+		return &kubernetes.ServiceEndpoints{ServiceName: "web"}, nil
+	}
+	if ip == "172.30.0.10" && port == 80 {
+		return &kubernetes.ServiceEndpoints{ServiceName: "web", Endpoints: []string{"10.128.0.5:8080", "10.128.0.6:8080"}}, nil
+	}
 	return nil, errors.New("notFound")
 }
 
+func TestTransform_K8sEnrichment(t *testing.T) {
+	kubernetes.Data = &fakeKubeData{}
+	nt := Network{
+		TransformNetwork: api.TransformNetwork{
+			Rules: api.NetworkTransformRules{{
+				Type:   api.OpAddKubernetes,
+				Input:  "SrcAddr",
+				Output: "SrcK8s",
+			}},
+		},
+	}
+	require.NoError(t, nt.compileRules(opMetrics, ""))
+	out, _ := nt.Transform(config.GenericMap{"SrcAddr": "5.6.7.8"})
+	assert.Equal(t, "backup", out["SrcK8s_OwnerName"])
+	assert.Equal(t, "CronJob", out["SrcK8s_OwnerType"])
+	assert.Equal(t, "ns/macvlan", out["SrcK8s_Network"])
+	assert.Equal(t, "1234", out["SrcK8s_NamespaceLabel_cost-center"])
+	assert.Equal(t, "network", out["SrcK8s_Annotation_team"])
+	assert.Equal(t, "us-east-1a", out["SrcK8s_NodeLabel_topology.kubernetes.io/zone"])
+	// the immediate owner is only set when the owner depth is configured
+	assert.NotContains(t, out, "SrcK8s_ImmediateOwnerName")
+	assert.NotContains(t, out, "SrcK8s_ImmediateOwnerType")
+
+	nt.Kubernetes = &api.NetworkTransformKubernetes{OwnerDepth: 3}
+	require.NoError(t, nt.compileRules(opMetrics, ""))
+	out, _ = nt.Transform(config.GenericMap{"SrcAddr": "5.6.7.8"})
+	assert.Equal(t, "backup", out["SrcK8s_OwnerName"])
+	assert.Equal(t, "backup-27800000", out["SrcK8s_ImmediateOwnerName"])
+	assert.Equal(t, "Job", out["SrcK8s_ImmediateOwnerType"])
+	// not set when it's the same as the top-level owner
+	out, _ = nt.Transform(config.GenericMap{"SrcAddr": "1.2.3.4"})
+	assert.NotContains(t, out, "SrcK8s_ImmediateOwnerName")
+}
+
+func TestTransform_K8sServiceEndpoints(t *testing.T) {
+	kubernetes.Data = &fakeKubeData{}
+	nt := Network{
+		TransformNetwork: api.TransformNetwork{
+			Rules: api.NetworkTransformRules{{
+				Type:   api.OpAddKubernetes,
+				Input:  "SrcAddr",
+				Output: "SrcK8s",
+			}, {
+				Type:   api.OpAddKubernetes,
+				Input:  "DstAddr",
+				Output: "DstK8s",
+			}},
+			Kubernetes: &api.NetworkTransformKubernetes{ServiceEndpoints: &api.NetworkTransformKubeServiceEndpoints{}},
+		},
+	}
+	require.NoError(t, nt.compileRules(opMetrics, ""))
+	// before load balancing, the destination is the Service
+	out, _ := nt.Transform(config.GenericMap{"SrcAddr": "1.2.3.4", "SrcPort": 45678, "DstAddr": "172.30.0.10", "DstPort": 80, "Proto": 6})
+	assert.Equal(t, "web", out["DstK8s_ServiceName"])
+	assert.Equal(t, []string{"10.128.0.5:8080", "10.128.0.6:8080"}, out["DstK8s_Endpoints"])
+	assert.NotContains(t, out, "SrcK8s_ServiceName")
+	// after load balancing, the destination is the Pod backing the Service
+	out, _ = nt.Transform(config.GenericMap{"SrcAddr": "1.2.3.4", "SrcPort": 45678, "DstAddr": "5.6.7.8", "DstPort": 8080, "Proto": "tcp"})
+	assert.Equal(t, "pod", out["DstK8s_Name"])
+	assert.Equal(t, "web", out["DstK8s_ServiceName"])
+	assert.NotContains(t, out, "DstK8s_Endpoints")
+	// in the response, the source is the Pod
+	out, _ = nt.Transform(config.GenericMap{"SrcAddr": "5.6.7.8", "SrcPort": 8080, "DstAddr": "1.2.3.4", "DstPort": 45678, "Proto": 6})
+	assert.Equal(t, "web", out["SrcK8s_ServiceName"])
+	// not enabled
+	nt.Kubernetes = nil
+	require.NoError(t, nt.compileRules(opMetrics, ""))
+	out, _ = nt.Transform(config.GenericMap{"DstAddr": "5.6.7.8", "DstPort": 8080, "Proto": 6})
+	assert.NotContains(t, out, "DstK8s_ServiceName")
+}
+
+func TestTransform_NetworkPolicy(t *testing.T) {
+	policies, err := kubernetes.NewPolicyEvaluator(fake.NewSimpleClientset(&networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "allow-backup", Namespace: "ns"},
+		Spec: networkingv1.NetworkPolicySpec{
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}}},
+			}},
+		},
+	}), &fakeKubeData{})
+	require.NoError(t, err)
+	nt := Network{
+		TransformNetwork: api.TransformNetwork{
+			Rules: api.NetworkTransformRules{{
+				Type: api.OpAddNetworkPolicy,
+			}},
+			NetworkPolicyInfo: &api.NetworkTransformNetworkPolicyInfo{SrcIPField: "SrcIP", DstIPField: "DstIP"},
+		},
+		policies: policies,
+	}
+	require.NoError(t, nt.compileRules(opMetrics, ""))
+	out, _ := nt.Transform(config.GenericMap{"SrcIP": "10.1.2.3", "DstIP": "5.6.7.8", "DstPort": 443, "Proto": 6})
+	assert.Equal(t, "allow", out["PolicyVerdict"])
+	assert.Equal(t, "ns/allow-backup", out["PolicyName"])
+	out, _ = nt.Transform(config.GenericMap{"SrcIP": "1.2.3.4", "DstIP": "5.6.7.8", "DstPort": 443, "Proto": 6})
+	assert.Equal(t, "deny", out["PolicyVerdict"])
+	assert.Equal(t, "ns/allow-backup", out["PolicyName"])
+	// neither IP is a Pod
+	out, _ = nt.Transform(config.GenericMap{"SrcIP": "1.2.3.4", "DstIP": "8.8.8.8", "DstPort": 443, "Proto": 6})
+	assert.NotContains(t, out, "PolicyVerdict")
+
+	nt.Rules[0].Output = "Policy"
+	require.NoError(t, nt.compileRules(opMetrics, ""))
+	out, _ = nt.Transform(config.GenericMap{"SrcIP": "10.1.2.3", "DstIP": "5.6.7.8", "DstPort": 443, "Proto": 6})
+	assert.Equal(t, "allow", out["Policy_PolicyVerdict"])
+}
+
+func TestKubeProtocol(t *testing.T) {
+	assert.Equal(t, "TCP", kubeProtocol(6))
+	assert.Equal(t, "UDP", kubeProtocol("17"))
+	assert.Equal(t, "SCTP", kubeProtocol("sctp"))
+	assert.Equal(t, "", kubeProtocol(1))
+	assert.Equal(t, "", kubeProtocol(nil))
+}
+
+type fakeCounter struct {
+	prometheus.Counter
+	count int
+}
+
+func (c *fakeCounter) Inc() {
+	c.count++
+}
+
+func TestTransform_K8sHistory(t *testing.T) {
+	kubernetes.Data = &fakeKubeData{}
+	lookups := &fakeCounter{}
+	nt := Network{
+		TransformNetwork: api.TransformNetwork{
+			Rules: api.NetworkTransformRules{{
+				Type:   api.OpAddKubernetes,
+				Input:  "SrcAddr",
+				Output: "SrcK8s",
+			}},
+			Kubernetes: &api.NetworkTransformKubernetes{TimestampField: "TimeReceived"},
+		},
+		kubeHistoryLookups: lookups,
+	}
+	require.NoError(t, nt.compileRules(opMetrics, ""))
+	out, _ := nt.Transform(config.GenericMap{"SrcAddr": "9.9.9.9", "TimeReceived": 999_000})
+	assert.Equal(t, "deleted-pod", out["SrcK8s_Name"])
+	assert.Equal(t, 1, lookups.count)
+	out, _ = nt.Transform(config.GenericMap{"SrcAddr": "9.9.9.9", "TimeReceived": 1_001_000})
+	assert.Equal(t, "new-pod", out["SrcK8s_Name"])
+	// without timestamp, the current owner is returned
+	out, _ = nt.Transform(config.GenericMap{"SrcAddr": "9.9.9.9", "TimeFlowEndMs": 999_000})
+	assert.Equal(t, "new-pod", out["SrcK8s_Name"])
+	assert.Equal(t, 1, lookups.count)
+}
+
 func Test_Categorize(t *testing.T) {
 	entry := config.GenericMap{
 		"addr1": "10.1.2.3",
@@ -397,7 +592,7 @@ func Test_Categorize(t *testing.T) {
 		},
 	}
 
-	tr, err := NewTransformNetwork(cfg)
+	tr, err := NewTransformNetwork(opMetrics, cfg)
 	require.NoError(t, err)
 
 	output, ok := tr.Transform(entry)
@@ -432,7 +627,7 @@ func Test_ReinterpretDirection(t *testing.T) {
 		},
 	}
 
-	tr, err := NewTransformNetwork(cfg)
+	tr, err := NewTransformNetwork(opMetrics, cfg)
 	require.NoError(t, err)
 
 	output, ok := tr.Transform(config.GenericMap{
@@ -552,7 +747,7 @@ func Test_ReinterpretDirection(t *testing.T) {
 
 func Test_ValidateReinterpretDirection(t *testing.T) {
 	// Missing reporter field
-	_, err := NewTransformNetwork(config.StageParam{
+	_, err := NewTransformNetwork(opMetrics, config.StageParam{
 		Transform: &config.Transform{
 			Network: &api.TransformNetwork{
 				Rules: []api.NetworkTransformRule{{
@@ -570,7 +765,7 @@ func Test_ValidateReinterpretDirection(t *testing.T) {
 	require.Contains(t, err.Error(), "missing ReporterIPField")
 
 	// Missing src field
-	_, err = NewTransformNetwork(config.StageParam{
+	_, err = NewTransformNetwork(opMetrics, config.StageParam{
 		Transform: &config.Transform{
 			Network: &api.TransformNetwork{
 				Rules: []api.NetworkTransformRule{{
@@ -588,7 +783,7 @@ func Test_ValidateReinterpretDirection(t *testing.T) {
 	require.Contains(t, err.Error(), "missing SrcHostField")
 
 	// Missing dst field
-	_, err = NewTransformNetwork(config.StageParam{
+	_, err = NewTransformNetwork(opMetrics, config.StageParam{
 		Transform: &config.Transform{
 			Network: &api.TransformNetwork{
 				Rules: []api.NetworkTransformRule{{
@@ -606,7 +801,7 @@ func Test_ValidateReinterpretDirection(t *testing.T) {
 	require.Contains(t, err.Error(), "missing DstHostField")
 
 	// Missing flow direction field
-	_, err = NewTransformNetwork(config.StageParam{
+	_, err = NewTransformNetwork(opMetrics, config.StageParam{
 		Transform: &config.Transform{
 			Network: &api.TransformNetwork{
 				Rules: []api.NetworkTransformRule{{
@@ -624,7 +819,7 @@ func Test_ValidateReinterpretDirection(t *testing.T) {
 	require.Contains(t, err.Error(), "missing FlowDirectionField")
 
 	// Missing if direction field does not trigger an error (this field will just not be populated)
-	tr, err := NewTransformNetwork(config.StageParam{
+	tr, err := NewTransformNetwork(opMetrics, config.StageParam{
 		Transform: &config.Transform{
 			Network: &api.TransformNetwork{
 				Rules: []api.NetworkTransformRule{{
@@ -657,3 +852,1044 @@ func Test_ValidateReinterpretDirection(t *testing.T) {
 		"FlowDirection": 1,
 	}, output)
 }
+
+func Test_InferDirection(t *testing.T) {
+	cfg := config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{{
+					Type: api.OpInferDirection,
+				}},
+				IPCategories: []api.NetworkTransformIPCategory{
+					{Name: "datacenter", CIDRs: []string{"10.0.0.0/8"}},
+					{Name: "office", CIDRs: []string{"192.168.0.0/16"}},
+					{Name: "customers", CIDRs: []string{"100.64.0.0/10"}},
+				},
+				DirectionInference: &api.NetworkTransformDirectionInference{
+					InternalCategories: []string{"datacenter", "office"},
+				},
+			},
+		},
+	}
+	tr, err := NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		src, dst  string
+		direction string
+	}{
+		{src: "10.0.0.1", dst: "192.168.0.1", direction: "internal"},
+		{src: "10.0.0.1", dst: "8.8.8.8", direction: "egress"},
+		{src: "8.8.8.8", dst: "192.168.0.1", direction: "ingress"},
+		{src: "100.64.0.1", dst: "100.64.0.2", direction: "transit"},
+		{src: "100.64.0.1", dst: "8.8.8.8", direction: "external"},
+	} {
+		output, ok := tr.Transform(config.GenericMap{"SrcAddr": tc.src, "DstAddr": tc.dst})
+		require.True(t, ok)
+		require.Equal(t, tc.direction, output["Direction"], "%s -> %s", tc.src, tc.dst)
+	}
+
+	// missing IP is ignored
+	output, ok := tr.Transform(config.GenericMap{"SrcAddr": "10.0.0.1"})
+	require.True(t, ok)
+	require.Equal(t, config.GenericMap{"SrcAddr": "10.0.0.1"}, output)
+}
+
+func Test_InferDirectionSwapToClientServer(t *testing.T) {
+	cfg := config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{{
+					Type:   api.OpInferDirection,
+					Output: "FlowDir",
+				}},
+				ServicesFile:  path.Join("netdb", "testdata", "etcServices.txt"),
+				ProtocolsFile: path.Join("netdb", "testdata", "etcProtocols.txt"),
+				IPCategories: []api.NetworkTransformIPCategory{
+					{Name: "datacenter", CIDRs: []string{"10.0.0.0/8"}},
+				},
+				DirectionInference: &api.NetworkTransformDirectionInference{
+					InternalCategories: []string{"datacenter"},
+					SwapToClientServer: true,
+				},
+			},
+		},
+	}
+	tr, err := NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+
+	// response from an external web server to an internal client
+	output, ok := tr.Transform(config.GenericMap{
+		"SrcAddr":     "8.8.8.8",
+		"DstAddr":     "10.0.0.1",
+		"SrcPort":     443,
+		"DstPort":     51234,
+		"Proto":       6,
+		"SrcK8S_Name": "",
+		"DstMac":      "0A:58:0A:80:00:01",
+		"Bytes":       100,
+	})
+	require.True(t, ok)
+	require.Equal(t, config.GenericMap{
+		"SrcAddr":         "10.0.0.1",
+		"DstAddr":         "8.8.8.8",
+		"SrcPort":         51234,
+		"DstPort":         443,
+		"Proto":           6,
+		"DstK8S_Name":     "",
+		"SrcMac":          "0A:58:0A:80:00:01",
+		"Bytes":           100,
+		"FlowDir":         "egress",
+		"FlowDir_Swapped": true,
+	}, output)
+
+	// request from the client is kept as is
+	output, ok = tr.Transform(config.GenericMap{
+		"SrcAddr": "10.0.0.1",
+		"DstAddr": "8.8.8.8",
+		"SrcPort": 51234,
+		"DstPort": 443,
+		"Proto":   6,
+	})
+	require.True(t, ok)
+	require.Equal(t, config.GenericMap{
+		"SrcAddr": "10.0.0.1",
+		"DstAddr": "8.8.8.8",
+		"SrcPort": 51234,
+		"DstPort": 443,
+		"Proto":   6,
+		"FlowDir": "egress",
+	}, output)
+}
+
+func Test_ValidateInferDirection(t *testing.T) {
+	cfg := config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{{
+					Type: api.OpInferDirection,
+				}},
+				IPCategories: []api.NetworkTransformIPCategory{
+					{Name: "datacenter", CIDRs: []string{"10.0.0.0/8"}},
+				},
+			},
+		},
+	}
+	_, err := NewTransformNetwork(opMetrics, cfg)
+	require.ErrorContains(t, err, "missing internalCategories")
+
+	cfg.Transform.Network.DirectionInference = &api.NetworkTransformDirectionInference{
+		InternalCategories: []string{"datacenter"},
+		SrcPrefix:          "Dst",
+	}
+	_, err = NewTransformNetwork(opMetrics, cfg)
+	require.ErrorContains(t, err, "srcPrefix and dstPrefix must differ")
+
+	cfg.Transform.Network.DirectionInference.SrcPrefix = ""
+	cfg.Transform.Network.IPCategories = nil
+	_, err = NewTransformNetwork(opMetrics, cfg)
+	require.ErrorContains(t, err, "no IP categories configured")
+}
+
+func Test_AddSubnetIPv6(t *testing.T) {
+	cfg := config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{
+					{Type: api.OpAddSubnet, Input: "addr1", Output: "subnet1", Parameters: "/24,/64"},
+					{Type: api.OpAddSubnet, Input: "addr2", Output: "subnet2", Parameters: "/24,/64"},
+					{Type: api.OpAddSubnet, Input: "addr2", Output: "subnet2_legacy", Parameters: "/24"},
+				},
+			},
+		},
+	}
+
+	tr, err := NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+
+	output, ok := tr.Transform(config.GenericMap{
+		"addr1": "10.1.2.3",
+		"addr2": "2001:db8:1:2:3::4",
+	})
+	require.True(t, ok)
+	require.Equal(t, config.GenericMap{
+		"addr1":          "10.1.2.3",
+		"subnet1":        "10.1.2.0/24",
+		"addr2":          "2001:db8:1:2:3::4",
+		"subnet2":        "2001:db8:1:2::/64",
+		"subnet2_legacy": "2001:d00::/24",
+	}, output)
+
+	// a single length longer than /32 only applies to IPv6 addresses
+	cfg.Transform.Network.Rules = []api.NetworkTransformRule{
+		{Type: api.OpAddSubnet, Input: "addr1", Output: "subnet1", Parameters: "/64"},
+		{Type: api.OpAddSubnet, Input: "addr2", Output: "subnet2", Parameters: "/64"},
+	}
+	tr, err = NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+	output, ok = tr.Transform(config.GenericMap{
+		"addr1": "10.1.2.3",
+		"addr2": "2001:db8:1:2:3::4",
+	})
+	require.True(t, ok)
+	require.Equal(t, config.GenericMap{
+		"addr1":   "10.1.2.3",
+		"addr2":   "2001:db8:1:2:3::4",
+		"subnet2": "2001:db8:1:2::/64",
+	}, output)
+
+	_, err = NewTransformNetwork(opMetrics, config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{
+					{Type: api.OpAddSubnet, Input: "addr1", Output: "subnet1", Parameters: "/64,/24"},
+				},
+			},
+		},
+	})
+	require.Error(t, err)
+}
+
+func Test_AddSubnetLabel(t *testing.T) {
+	cfg := config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{
+					{Type: api.OpAddSubnetLabel, Input: "addr1", Output: "label1"},
+					{Type: api.OpAddSubnetLabel, Input: "addr2", Output: "label2"},
+					{Type: api.OpAddSubnetLabel, Input: "addr3", Output: "label3"},
+					{Type: api.OpAddSubnetLabel, Input: "addr4", Output: "label4"},
+				},
+				SubnetLabels: []api.NetworkTransformSubnetLabel{{
+					Name:  "Datacenter",
+					CIDRs: []string{"10.0.0.0/8", "fd00::/8"},
+				}, {
+					Name:  "Database",
+					CIDRs: []string{"10.1.2.0/24"},
+				}},
+			},
+		},
+	}
+
+	tr, err := NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+
+	output, ok := tr.Transform(config.GenericMap{
+		"addr1": "10.1.2.3",
+		"addr2": "10.1.3.4",
+		"addr3": "fd00::1",
+		"addr4": "8.8.8.8",
+	})
+	require.True(t, ok)
+	require.Equal(t, config.GenericMap{
+		"addr1":       "10.1.2.3",
+		"label1":      "Database",
+		"label1_CIDR": "10.1.2.0/24",
+		"addr2":       "10.1.3.4",
+		"label2":      "Datacenter",
+		"label2_CIDR": "10.0.0.0/8",
+		"addr3":       "fd00::1",
+		"label3":      "Datacenter",
+		"label3_CIDR": "fd00::/8",
+		"addr4":       "8.8.8.8",
+	}, output)
+
+	// missing subnet labels configuration
+	_, err = NewTransformNetwork(opMetrics, config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{{Type: api.OpAddSubnetLabel, Input: "addr1", Output: "label1"}},
+			},
+		},
+	})
+	require.Error(t, err)
+}
+
+func Test_CategorizeFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := path.Join(dir, "categories.yaml")
+	require.NoError(t, os.WriteFile(yamlFile, []byte(`
+- name: Corporate
+  cidrs:
+    - 100.0.0.0/8
+    - fd00::/8
+`), 0o644))
+	csvFile := path.Join(dir, "categories.csv")
+	require.NoError(t, os.WriteFile(csvFile, []byte(`cidr,name
+# corporate datacenters
+100.1.0.0/16,Datacenter
+100.2.0.0/16, Datacenter
+10.0.0.0/8,Ignored as declared inline
+`), 0o644))
+
+	cfg := config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{
+					{Type: api.OpAddIPCategory, Input: "addr1", Output: "cat1"},
+					{Type: api.OpAddIPCategory, Input: "addr2", Output: "cat2"},
+					{Type: api.OpAddIPCategory, Input: "addr3", Output: "cat3"},
+					{Type: api.OpAddIPCategory, Input: "addr4", Output: "cat4"},
+				},
+				IPCategories: []api.NetworkTransformIPCategory{{
+					Name:  "Pods overlay",
+					CIDRs: []string{"10.0.0.0/8"},
+				}},
+				IPCategoriesFiles: []string{yamlFile, csvFile},
+				ReloadPeriod:      &api.Duration{Duration: time.Hour},
+			},
+		},
+	}
+
+	tr, err := NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+
+	entry := config.GenericMap{
+		"addr1": "10.1.2.3",
+		"addr2": "100.2.3.4",
+		"addr3": "100.3.4.5",
+		"addr4": "fd00::1",
+	}
+	output, ok := tr.Transform(entry)
+	require.True(t, ok)
+	require.Equal(t, "Pods overlay", output["cat1"])
+	require.Equal(t, "Datacenter", output["cat2"])
+	require.Equal(t, "Corporate", output["cat3"])
+	require.Equal(t, "Corporate", output["cat4"])
+
+	// Modifying a file and reloading invalidates cached categories
+	require.NoError(t, os.WriteFile(csvFile, []byte("100.3.0.0/16,Lab\n"), 0o644))
+	tr.(*Network).reloadIPCategories()
+	output, ok = tr.Transform(entry)
+	require.True(t, ok)
+	require.Equal(t, "Pods overlay", output["cat1"])
+	require.Equal(t, "Corporate", output["cat2"])
+	require.Equal(t, "Lab", output["cat3"])
+
+	// A wrong file doesn't override the current categories
+	require.NoError(t, os.WriteFile(csvFile, []byte("not-a-cidr,Lab\n"), 0o644))
+	tr.(*Network).reloadIPCategories()
+	output, ok = tr.Transform(entry)
+	require.True(t, ok)
+	require.Equal(t, "Lab", output["cat3"])
+}
+
+func Test_AddASN(t *testing.T) {
+	cfg := config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{
+					{Type: api.OpAddASN, Input: "SrcAddr", Output: "Src"},
+					{Type: api.OpAddASN, Input: "DstAddr", Output: "Dst"},
+				},
+				ASNDBPath: "asn/testdata/GeoLite2-ASN-test.mmdb",
+			},
+		},
+	}
+
+	tr, err := NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+
+	output, ok := tr.Transform(config.GenericMap{
+		"SrcAddr": "10.0.0.1",
+		"DstAddr": "2001:4860:4860::8888",
+	})
+	require.True(t, ok)
+	require.Equal(t, config.GenericMap{
+		"SrcAddr":   "10.0.0.1",
+		"DstAddr":   "2001:4860:4860::8888",
+		"Dst_ASN":   uint32(15169),
+		"Dst_ASOrg": "GOOGLE",
+	}, output)
+
+	// no output: fields are not prefixed
+	cfg.Transform.Network.Rules = []api.NetworkTransformRule{{Type: api.OpAddASN, Input: "DstAddr"}}
+	tr, err = NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+	output, ok = tr.Transform(config.GenericMap{"DstAddr": "2001:4860:4860::8888"})
+	require.True(t, ok)
+	require.Equal(t, config.GenericMap{
+		"DstAddr": "2001:4860:4860::8888",
+		"ASN":     uint32(15169),
+		"ASOrg":   "GOOGLE",
+	}, output)
+
+	// missing or invalid database
+	cfg.Transform.Network.ASNDBPath = ""
+	_, err = NewTransformNetwork(opMetrics, cfg)
+	require.Error(t, err)
+	cfg.Transform.Network.ASNDBPath = "asn/testdata/not-found.mmdb"
+	_, err = NewTransformNetwork(opMetrics, cfg)
+	require.Error(t, err)
+}
+
+func Test_AddMacVendor(t *testing.T) {
+	cfg := config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{
+					{Type: api.OpAddMacVendor, Input: "SrcMac", Output: "Src"},
+					{Type: api.OpAddMacVendor, Input: "DstMac", Output: "Dst"},
+				},
+				MacVendorFiles: []string{"oui/testdata/oui.csv", "oui/testdata/oui36.csv"},
+			},
+		},
+	}
+
+	tr, err := NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+
+	output, ok := tr.Transform(config.GenericMap{
+		"SrcMac": "70:B3:D5:12:3A:BC",
+		"DstMac": "FF:FF:FF:FF:FF:FF",
+	})
+	require.True(t, ok)
+	require.Equal(t, config.GenericMap{
+		"SrcMac":                     "70:B3:D5:12:3A:BC",
+		"DstMac":                     "FF:FF:FF:FF:FF:FF",
+		"Src_MacVendor":              "Amfitech ApS",
+		"Src_MacLocallyAdministered": false,
+		"Src_MacMulticast":           false,
+		"Dst_MacLocallyAdministered": true,
+		"Dst_MacMulticast":           true,
+	}, output)
+
+	// invalid MAC is ignored
+	output, ok = tr.Transform(config.GenericMap{"SrcMac": "not-a-mac"})
+	require.True(t, ok)
+	require.Equal(t, config.GenericMap{"SrcMac": "not-a-mac"}, output)
+
+	// no output: fields are not prefixed
+	cfg.Transform.Network.Rules = []api.NetworkTransformRule{{Type: api.OpAddMacVendor, Input: "SrcMac"}}
+	tr, err = NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+	output, ok = tr.Transform(config.GenericMap{"SrcMac": "70:B3:D5:12:3A:BC"})
+	require.True(t, ok)
+	require.Equal(t, config.GenericMap{
+		"SrcMac":                 "70:B3:D5:12:3A:BC",
+		"MacVendor":              "Amfitech ApS",
+		"MacLocallyAdministered": false,
+		"MacMulticast":           false,
+	}, output)
+
+	// missing or invalid files
+	cfg.Transform.Network.MacVendorFiles = nil
+	_, err = NewTransformNetwork(opMetrics, cfg)
+	require.Error(t, err)
+	cfg.Transform.Network.MacVendorFiles = []string{"oui/testdata/not-found.csv"}
+	_, err = NewTransformNetwork(opMetrics, cfg)
+	require.Error(t, err)
+}
+
+func Test_AddCommunityID(t *testing.T) {
+	cfg := config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{
+					{Type: api.OpAddCommunityID},
+				},
+			},
+		},
+	}
+	tr, err := NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+	communityID := func(entry config.GenericMap) interface{} {
+		output, ok := tr.Transform(entry)
+		require.True(t, ok)
+		return output["CommunityID"]
+	}
+
+	// reference value from the specification
+	tcp := config.GenericMap{"SrcAddr": "128.232.110.120", "DstAddr": "66.35.250.204", "SrcPort": 34855, "DstPort": 80, "Proto": 6}
+	require.Equal(t, "1:LQU9qZlK+B5F3KDmev6m5PMibrg=", communityID(tcp))
+	// both directions get the same ID
+	require.Equal(t, "1:LQU9qZlK+B5F3KDmev6m5PMibrg=", communityID(config.GenericMap{
+		"SrcAddr": "66.35.250.204", "DstAddr": "128.232.110.120", "SrcPort": 80, "DstPort": 34855, "Proto": 6,
+	}))
+
+	// ICMP echo request and reply are bidirectional
+	request := communityID(config.GenericMap{"SrcAddr": "10.0.0.1", "DstAddr": "10.0.0.2", "IcmpType": 8, "IcmpCode": 0, "Proto": 1})
+	reply := communityID(config.GenericMap{"SrcAddr": "10.0.0.2", "DstAddr": "10.0.0.1", "IcmpType": 0, "IcmpCode": 0, "Proto": 1})
+	require.Equal(t, request, reply)
+	// while destination unreachable is one-way
+	unreachable := communityID(config.GenericMap{"SrcAddr": "10.0.0.2", "DstAddr": "10.0.0.1", "IcmpType": 3, "IcmpCode": 3, "Proto": 1})
+	unreachableBack := communityID(config.GenericMap{"SrcAddr": "10.0.0.1", "DstAddr": "10.0.0.2", "IcmpType": 3, "IcmpCode": 3, "Proto": 1})
+	require.NotEqual(t, unreachable, unreachableBack)
+	// ICMPv6 uses its own types
+	request = communityID(config.GenericMap{"SrcAddr": "fe80::1", "DstAddr": "fe80::2", "IcmpType": 128, "IcmpCode": 0, "Proto": 58})
+	reply = communityID(config.GenericMap{"SrcAddr": "fe80::2", "DstAddr": "fe80::1", "IcmpType": 129, "IcmpCode": 0, "Proto": 58})
+	require.Equal(t, request, reply)
+
+	// protocols without ports ignore the port fields
+	require.Equal(t,
+		communityID(config.GenericMap{"SrcAddr": "10.0.0.1", "DstAddr": "10.0.0.2", "Proto": 47}),
+		communityID(config.GenericMap{"SrcAddr": "10.0.0.2", "DstAddr": "10.0.0.1", "SrcPort": 1, "DstPort": 2, "Proto": 47}))
+
+	// invalid or mixed addresses are ignored
+	require.Nil(t, communityID(config.GenericMap{"SrcAddr": "10.0.0.1", "DstAddr": "fe80::1", "Proto": 6}))
+	require.Nil(t, communityID(config.GenericMap{"SrcAddr": "10.0.0.1", "Proto": 6}))
+
+	// custom fields, output and seed
+	cfg.Transform.Network.Rules[0].Output = "flow_id"
+	cfg.Transform.Network.CommunityIDInfo = &api.NetworkTransformCommunityIDInfo{
+		SrcIPField: "src", DstIPField: "dst", SrcPortField: "sport", DstPortField: "dport", ProtocolField: "proto", Seed: 1,
+	}
+	tr, err = NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+	output, ok := tr.Transform(config.GenericMap{"src": "128.232.110.120", "dst": "66.35.250.204", "sport": 34855, "dport": 80, "proto": 6})
+	require.True(t, ok)
+	require.Regexp(t, "^1:[A-Za-z0-9+/]{27}=$", output["flow_id"])
+	require.NotEqual(t, "1:LQU9qZlK+B5F3KDmev6m5PMibrg=", output["flow_id"])
+}
+
+func Test_AddThreatIntel(t *testing.T) {
+	cfg := config.StageParam{
+		Name: "threat-intel",
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{
+					{Type: api.OpAddThreatIntel},
+				},
+				ThreatIntel: &api.NetworkTransformThreatIntel{
+					Lists: []api.NetworkTransformThreatList{
+						{Name: "drop", File: "threat/testdata/drop.txt", Category: "hijacked"},
+						{Name: "feed", File: "threat/testdata/blocklist.csv"},
+						{Name: "stix", File: "threat/testdata/indicators.json"},
+					},
+					DomainFields: []string{"DstHostName"},
+				},
+			},
+		},
+	}
+	tr, err := NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+	network := tr.(*Network)
+	counters := make([]*fakeCounter, len(network.threatLists))
+	for i := range network.threatLists {
+		counters[i] = &fakeCounter{}
+		network.threatLists[i].matches = counters[i]
+	}
+
+	output, ok := tr.Transform(config.GenericMap{
+		"SrcAddr":     "192.0.2.10",
+		"DstAddr":     "198.51.100.7",
+		"DstHostName": "www.malware.example",
+	})
+	require.True(t, ok)
+	require.Equal(t, config.GenericMap{
+		"SrcAddr":        "192.0.2.10",
+		"DstAddr":        "198.51.100.7",
+		"DstHostName":    "www.malware.example",
+		"ThreatMatch":    "SrcAddr,DstAddr,DstHostName",
+		"ThreatSource":   "drop,feed",
+		"ThreatCategory": "hijacked,botnet",
+	}, output)
+
+	output, ok = tr.Transform(config.GenericMap{"SrcAddr": "10.0.0.1", "DstAddr": "198.51.100.1"})
+	require.True(t, ok)
+	require.Equal(t, "DstAddr", output["ThreatMatch"])
+	require.Equal(t, "stix", output["ThreatSource"])
+	require.Equal(t, "malicious-activity", output["ThreatCategory"])
+
+	// no match
+	output, ok = tr.Transform(config.GenericMap{"SrcAddr": "10.0.0.1", "DstAddr": "10.0.0.2"})
+	require.True(t, ok)
+	require.Equal(t, config.GenericMap{"SrcAddr": "10.0.0.1", "DstAddr": "10.0.0.2"}, output)
+
+	require.Equal(t, []int{1, 1, 1}, []int{counters[0].count, counters[1].count, counters[2].count})
+}
+
+func Test_ValidateThreatIntel(t *testing.T) {
+	cfg := config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{
+					{Type: api.OpAddThreatIntel},
+				},
+			},
+		},
+	}
+	_, err := NewTransformNetwork(opMetrics, cfg)
+	require.ErrorContains(t, err, "no threat lists configured")
+
+	cfg.Transform.Network.ThreatIntel = &api.NetworkTransformThreatIntel{
+		Lists: []api.NetworkTransformThreatList{
+			{Name: "drop", File: "threat/testdata/drop.txt"},
+			{Name: "drop", File: "threat/testdata/blocklist.csv"},
+		},
+	}
+	_, err = NewTransformNetwork(opMetrics, cfg)
+	require.ErrorContains(t, err, "duplicate name")
+
+	cfg.Transform.Network.ThreatIntel.Lists = []api.NetworkTransformThreatList{{Name: "drop", File: "threat/testdata/drop.txt", Format: "xml"}}
+	_, err = NewTransformNetwork(opMetrics, cfg)
+	require.ErrorContains(t, err, "unknown format")
+}
+
+func Test_AddLocationFromLocalDB(t *testing.T) {
+	cfg := config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{
+					{Type: api.OpAddLocation, Input: "DstAddr", Output: "DstLocation"},
+				},
+				LocationDBPath: "location/testdata/GeoLite2-City-test.mmdb",
+			},
+		},
+	}
+
+	tr, err := NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+
+	output, ok := tr.Transform(config.GenericMap{"DstAddr": "8.8.8.8"})
+	require.True(t, ok)
+	require.Equal(t, config.GenericMap{
+		"DstAddr":                     "8.8.8.8",
+		"DstLocation_CountryName":     "US",
+		"DstLocation_CountryLongName": "United States",
+		"DstLocation_RegionName":      "California",
+		"DstLocation_CityName":        "Mountain View",
+		"DstLocation_Latitude":        "37.386000",
+		"DstLocation_Longitude":       "-122.083800",
+	}, output)
+
+	// invalid database or checksum are reported at startup
+	cfg.Transform.Network.LocationDBPath = "location/testdata/notfound.mmdb"
+	_, err = NewTransformNetwork(opMetrics, cfg)
+	require.Error(t, err)
+	cfg.Transform.Network.LocationDBPath = "location/testdata/GeoLite2-City-test.mmdb"
+	cfg.Transform.Network.LocationDBChecksumPath = "location/testdata/notfound.sha256"
+	_, err = NewTransformNetwork(opMetrics, cfg)
+	require.Error(t, err)
+}
+
+func Test_AddServerPort(t *testing.T) {
+	overridesFile := path.Join(t.TempDir(), "services")
+	require.NoError(t, os.WriteFile(overridesFile, []byte("kafka 9092/tcp\n"), 0600))
+	cfg := config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{
+					{Type: api.OpAddServerPort},
+					{Type: api.OpAddService, Input: "DstPort", Output: "DstService", Parameters: "Proto"},
+				},
+				ServicesFile:         path.Join("netdb", "testdata", "etcServices.txt"),
+				ProtocolsFile:        path.Join("netdb", "testdata", "etcProtocols.txt"),
+				ServiceOverridesFile: overridesFile,
+				ServiceOverrides:     []api.NetworkTransformServiceOverride{{Port: 8443, Name: "internal-api"}},
+			},
+		},
+	}
+	tr, err := NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name        string
+		entry       config.GenericMap
+		serverPort  int
+		serviceName string
+	}{{
+		name:        "overridden service, client to server",
+		entry:       config.GenericMap{"SrcPort": 45679, "DstPort": 9092, "Proto": 6},
+		serverPort:  9092,
+		serviceName: "kafka",
+	}, {
+		name:        "overridden service, server to client",
+		entry:       config.GenericMap{"SrcPort": 9092, "DstPort": 45679, "Proto": 6},
+		serverPort:  9092,
+		serviceName: "kafka",
+	}, {
+		name:        "override for any protocol",
+		entry:       config.GenericMap{"SrcPort": 8443, "DstPort": 45679, "Proto": "udp"},
+		serverPort:  8443,
+		serviceName: "internal-api",
+	}, {
+		name:        "known services: lower port",
+		entry:       config.GenericMap{"SrcPort": 443, "DstPort": 22, "Proto": 6},
+		serverPort:  22,
+		serviceName: "ssh",
+	}, {
+		name:       "well-known port without service name",
+		entry:      config.GenericMap{"SrcPort": 45679, "DstPort": uint16(1019), "Proto": 6},
+		serverPort: 1019,
+	}, {
+		name:       "unknown ports: lower port",
+		entry:      config.GenericMap{"SrcPort": 50000, "DstPort": 60000, "Proto": 6},
+		serverPort: 50000,
+	}, {
+		name: "conntrack connection record: B side",
+		entry: config.GenericMap{"SrcPort": 80, "DstPort": 45679, "Proto": 6,
+			api.RecordTypeFieldName: api.ConnTrackOutputRecordTypeName("NewConnection")},
+		serverPort: 45679,
+	}, {
+		name: "conntrack flow log: inferred",
+		entry: config.GenericMap{"SrcPort": 80, "DstPort": 45679, "Proto": 6,
+			api.RecordTypeFieldName: api.ConnTrackOutputRecordTypeName("FlowLog")},
+		serverPort:  80,
+		serviceName: "http",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			output, ok := tr.Transform(tc.entry)
+			require.True(t, ok)
+			require.Equal(t, tc.serverPort, output["ServerPort"])
+			if tc.serviceName == "" {
+				require.NotContains(t, output, "ServiceName")
+			} else {
+				require.Equal(t, tc.serviceName, output["ServiceName"])
+			}
+		})
+	}
+
+	// overrides also apply to add_service
+	output, _ := tr.Transform(config.GenericMap{"SrcPort": 45679, "DstPort": 9092, "Proto": 6})
+	require.Equal(t, "kafka", output["DstService"])
+
+	// missing ports
+	output, _ = tr.Transform(config.GenericMap{"SrcPort": 45679, "Proto": 6})
+	require.NotContains(t, output, "ServerPort")
+
+	// invalid overrides
+	cfg.Transform.Network.ServiceOverrides = []api.NetworkTransformServiceOverride{{Port: 8443, Protocol: "unknown", Name: "internal-api"}}
+	_, err = NewTransformNetwork(opMetrics, cfg)
+	require.Error(t, err)
+}
+
+func Test_DecodeFields(t *testing.T) {
+	cfg := config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{
+					{Type: api.OpDecodeProtocol, Input: "Proto", Output: "ProtoName"},
+					{Type: api.OpDecodeEtherType, Input: "Etype", Output: "EtypeName"},
+					{Type: api.OpDecodeTCPFlags, Input: "Flags", Output: "FlagNames"},
+					{Type: api.OpDecodeTCPFlags, Input: "Flags", Output: "FlagList", Parameters: "list"},
+					{Type: api.OpDecodeICMP, Output: "Icmp"},
+				},
+				ServicesFile:  path.Join("netdb", "testdata", "etcServices.txt"),
+				ProtocolsFile: path.Join("netdb", "testdata", "etcProtocols.txt"),
+			},
+		},
+	}
+	tr, err := NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+
+	output, _ := tr.Transform(config.GenericMap{"Proto": 6, "Etype": 2048, "Flags": uint16(0x12), "IcmpType": 0, "IcmpCode": 0})
+	require.Equal(t, "tcp", output["ProtoName"])
+	require.Equal(t, "IPv4", output["EtypeName"])
+	require.Equal(t, "SYN,ACK", output["FlagNames"])
+	require.Equal(t, []string{"SYN", "ACK"}, output["FlagList"])
+	// not an ICMP flow
+	require.NotContains(t, output, "Icmp_TypeName")
+	require.NotContains(t, output, "Icmp_CodeName")
+
+	output, _ = tr.Transform(config.GenericMap{"Proto": 1, "Etype": 2048, "Flags": 0, "IcmpType": 3, "IcmpCode": 1})
+	require.Equal(t, "icmp", output["ProtoName"])
+	require.Equal(t, "", output["FlagNames"])
+	require.Equal(t, []string{}, output["FlagList"])
+	require.Equal(t, "Destination Unreachable", output["Icmp_TypeName"])
+	require.Equal(t, "Host Unreachable", output["Icmp_CodeName"])
+
+	output, _ = tr.Transform(config.GenericMap{"Proto": "ipv6-icmp", "Etype": 34525, "IcmpType": 128, "IcmpCode": 0})
+	require.Equal(t, "IPv6", output["EtypeName"])
+	require.Equal(t, "Echo Request", output["Icmp_TypeName"])
+	require.NotContains(t, output, "Icmp_CodeName")
+	require.NotContains(t, output, "ProtoName")
+	require.NotContains(t, output, "FlagNames")
+
+	// unknown values
+	output, _ = tr.Transform(config.GenericMap{"Proto": 9999, "Etype": 0x1234})
+	require.NotContains(t, output, "ProtoName")
+	require.NotContains(t, output, "EtypeName")
+
+	// custom ICMP fields, without output prefix
+	cfg.Transform.Network.Rules = []api.NetworkTransformRule{{Type: api.OpDecodeICMP}}
+	cfg.Transform.Network.ICMPInfo = &api.NetworkTransformICMPInfo{TypeField: "Type", CodeField: "Code", ProtocolField: "Protocol"}
+	tr, err = NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+	output, _ = tr.Transform(config.GenericMap{"Protocol": 1, "Type": 11, "Code": 0})
+	require.Equal(t, "Time Exceeded", output["TypeName"])
+	require.Equal(t, "Time to Live Exceeded in Transit", output["CodeName"])
+
+	// invalid TCP flags parameters
+	cfg.Transform.Network.Rules = []api.NetworkTransformRule{{Type: api.OpDecodeTCPFlags, Input: "Flags", Output: "FlagNames", Parameters: "array"}}
+	_, err = NewTransformNetwork(opMetrics, cfg)
+	require.Error(t, err)
+}
+
+func TestTransform_K8sClusters(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"east", "west"} {
+		require.NoError(t, os.WriteFile(path.Join(dir, name+".yaml"),
+			[]byte("pods:\n  - name: pod-"+name+"\n    namespace: ns\n    ips: [10.128.0.5]\n"), 0600))
+	}
+	cfg := config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{{Type: api.OpAddKubernetes, Input: "SrcAddr", Output: "SrcK8S"}},
+				Kubernetes: &api.NetworkTransformKubernetes{
+					ClusterField: "Topic",
+					Clusters: []api.NetworkTransformKubeCluster{
+						{Name: "east", InventoryFile: path.Join(dir, "east.yaml"), Values: []string{"flows-east"}},
+						{Name: "west", InventoryFile: path.Join(dir, "west.yaml"), Values: []string{"flows-west"}},
+					},
+				},
+			},
+		},
+	}
+	tr, err := NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+
+	output, _ := tr.Transform(config.GenericMap{"SrcAddr": "10.128.0.5", "Topic": "flows-west"})
+	require.Equal(t, "west", output["SrcK8S_Cluster"])
+	require.Equal(t, "pod-west", output["SrcK8S_Name"])
+	output, _ = tr.Transform(config.GenericMap{"SrcAddr": "10.128.0.5", "Topic": "flows-east"})
+	require.Equal(t, "east", output["SrcK8S_Cluster"])
+	require.Equal(t, "pod-east", output["SrcK8S_Name"])
+	output, _ = tr.Transform(config.GenericMap{"SrcAddr": "10.128.0.5", "Topic": "flows-north"})
+	require.NotContains(t, output, "SrcK8S_Cluster")
+	require.NotContains(t, output, "SrcK8S_Name")
+}
+
+func TestTransform_K8sInventoryKeepsGlobalData(t *testing.T) {
+	dir := t.TempDir()
+	inventories := map[string]string{}
+	for _, name := range []string{"first", "second"} {
+		inventories[name] = path.Join(dir, name+".yaml")
+		require.NoError(t, os.WriteFile(inventories[name],
+			[]byte("pods:\n  - name: pod-"+name+"\n    namespace: ns\n    ips: [10.128.0.5]\n"), 0600))
+	}
+	fake := &fakeKubeData{}
+	kubernetes.Data = fake
+	newStage := func(name string) Transformer {
+		tr, err := NewTransformNetwork(opMetrics, config.StageParam{
+			Name: "test_inventory_" + name,
+			Transform: &config.Transform{
+				Network: &api.TransformNetwork{
+					Rules:      []api.NetworkTransformRule{{Type: api.OpAddKubernetes, Input: "SrcAddr", Output: "SrcK8S"}},
+					Kubernetes: &api.NetworkTransformKubernetes{InventoryFile: inventories[name]},
+				},
+			},
+		})
+		require.NoError(t, err)
+		return tr
+	}
+	first, second := newStage("first"), newStage("second")
+
+	// each stage resolves through its own inventory, without replacing the global data
+	require.Same(t, fake, kubernetes.Data)
+	output, _ := first.Transform(config.GenericMap{"SrcAddr": "10.128.0.5"})
+	require.Equal(t, "pod-first", output["SrcK8S_Name"])
+	output, _ = second.Transform(config.GenericMap{"SrcAddr": "10.128.0.5"})
+	require.Equal(t, "pod-second", output["SrcK8S_Name"])
+}
+
+func Test_RuleConditions(t *testing.T) {
+	cfg := config.StageParam{
+		Name: "test_network_rule_conditions",
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules: []api.NetworkTransformRule{
+					{Type: api.OpAddSubnet, Input: "SrcAddr", Output: "SrcSubnet", Parameters: "/16", If: "inCIDR(SrcAddr, '10.128.0.0/14')"},
+					{Type: api.OpAddSubnet, Input: "DstAddr", Output: "DstSubnet", Parameters: "/24"},
+					// conditions see the fields added by the previous rules
+					{Type: api.OpAddIf, Input: "Bytes", Output: "Big", Parameters: ">1000", If: "has(SrcSubnet)"},
+				},
+			},
+		},
+	}
+	tr, err := NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+
+	output, _ := tr.Transform(config.GenericMap{"SrcAddr": "10.129.1.2", "DstAddr": "10.0.1.2", "Bytes": 2000})
+	require.Equal(t, config.GenericMap{
+		"SrcAddr":      "10.129.1.2",
+		"DstAddr":      "10.0.1.2",
+		"Bytes":        2000,
+		"SrcSubnet":    "10.129.0.0/16",
+		"DstSubnet":    "10.0.1.0/24",
+		"Big":          2000,
+		"Big_Evaluate": true,
+	}, output)
+
+	output, _ = tr.Transform(config.GenericMap{"SrcAddr": "192.168.1.2", "DstAddr": "10.0.1.2", "Bytes": 2000})
+	require.Equal(t, config.GenericMap{
+		"SrcAddr":   "192.168.1.2",
+		"DstAddr":   "10.0.1.2",
+		"Bytes":     2000,
+		"DstSubnet": "10.0.1.0/24",
+	}, output)
+
+	// invalid conditions are reported at startup
+	cfg.Name = "test_network_rule_conditions_invalid"
+	cfg.Transform.Network.Rules[2].If = "has(SrcSubnet"
+	_, err = NewTransformNetwork(opMetrics, cfg)
+	require.ErrorContains(t, err, "rule #2")
+}
+
+func Test_ScaleSampling(t *testing.T) {
+	info := &api.NetworkTransformSamplingInfo{
+		Exporters: []api.NetworkTransformExporterSampling{
+			{CIDRs: []string{"192.168.1.0/24", "192.168.2.1"}, Rate: 10},
+		},
+	}
+	cfg := config.StageParam{
+		Transform: &config.Transform{
+			Network: &api.TransformNetwork{
+				Rules:        []api.NetworkTransformRule{{Type: api.OpScaleSampling}},
+				SamplingInfo: info,
+			},
+		},
+	}
+	tr, err := NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+
+	// rate of the flow
+	output, _ := tr.Transform(config.GenericMap{"SamplingRate": uint64(100), "SamplerAddress": []byte{192, 168, 1, 1}, "Bytes": uint64(1500), "Packets": uint32(2)})
+	require.Equal(t, config.GenericMap{
+		"SamplingRate":     uint64(100),
+		"SamplerAddress":   []byte{192, 168, 1, 1},
+		"Bytes":            uint64(150000),
+		"Bytes_Raw":        uint64(1500),
+		"Packets":          uint64(200),
+		"Packets_Raw":      uint32(2),
+		"SamplingUpscaled": true,
+	}, output)
+
+	// upscaled records are not scaled twice
+	again, _ := tr.Transform(output)
+	require.Equal(t, output, again)
+
+	// rate of the exporter, when the flow rate is unknown
+	output, _ = tr.Transform(config.GenericMap{"SamplingRate": 0, "SamplerAddress": []byte{192, 168, 1, 7}, "Bytes": 1500.0, "Packets": 2.0})
+	require.Equal(t, 15000.0, output["Bytes"])
+	require.Equal(t, 1500.0, output["Bytes_Raw"])
+	require.Equal(t, 20.0, output["Packets"])
+	output, _ = tr.Transform(config.GenericMap{"SamplerAddress": "192.168.2.1", "Bytes": 1500})
+	require.Equal(t, config.GenericMap{
+		"SamplerAddress":   "192.168.2.1",
+		"Bytes":            15000,
+		"Bytes_Raw":        1500,
+		"SamplingUpscaled": true,
+	}, output)
+
+	// invalid negative rate of the flow: rate of the exporter
+	output, _ = tr.Transform(config.GenericMap{"SamplingRate": -5, "SamplerAddress": "192.168.2.1", "Bytes": 1500})
+	require.Equal(t, 15000, output["Bytes"])
+	require.Equal(t, 1500, output["Bytes_Raw"])
+
+	// unknown rate
+	output, _ = tr.Transform(config.GenericMap{"SamplerAddress": "192.168.3.1", "Bytes": 1500})
+	require.Equal(t, config.GenericMap{"SamplerAddress": "192.168.3.1", "Bytes": 1500}, output)
+
+	// default rate, custom fields
+	info.DefaultRate = 2
+	info.RateField = "Sampling"
+	info.Fields = []string{"Bytes"}
+	cfg.Transform.Network.Rules[0].Output = "Upscaled"
+	tr, err = NewTransformNetwork(opMetrics, cfg)
+	require.NoError(t, err)
+	output, _ = tr.Transform(config.GenericMap{"SamplerAddress": "192.168.3.1", "Bytes": 1500, "Packets": 2})
+	require.Equal(t, config.GenericMap{
+		"SamplerAddress": "192.168.3.1",
+		"Bytes":          3000,
+		"Bytes_Raw":      1500,
+		"Packets":        2,
+		"Upscaled":       true,
+	}, output)
+	output, _ = tr.Transform(config.GenericMap{"Sampling": 50, "SamplingRate": 100, "Bytes": 1500})
+	require.Equal(t, 75000, output["Bytes"])
+	output, _ = tr.Transform(config.GenericMap{"Sampling": -50, "SamplerAddress": "192.168.3.1", "Bytes": 1500})
+	require.Equal(t, 3000, output["Bytes"])
+
+	// invalid configurations
+	info.Exporters = []api.NetworkTransformExporterSampling{{CIDRs: []string{"192.168.1.0/33"}, Rate: 10}}
+	_, err = NewTransformNetwork(opMetrics, cfg)
+	require.Error(t, err)
+	info.Exporters = []api.NetworkTransformExporterSampling{{CIDRs: []string{"192.168.1.0/24"}}}
+	_, err = NewTransformNetwork(opMetrics, cfg)
+	require.Error(t, err)
+}
+
+func Test_CompileRulesErrors(t *testing.T) {
+	for _, rule := range []api.NetworkTransformRule{
+		{Type: api.OpAddRegexIf, Input: "SrcAddr", Output: "Match", Parameters: "10.(0"},
+		{Type: api.OpAddIf, Input: "Bytes", Output: "Big", Parameters: "> > 10"},
+		{Type: api.OpAddSubnet, Input: "SrcAddr", Output: "SrcSubnet", Parameters: "/33,/64"},
+		{Type: api.OpAddSubnet, Input: "SrcAddr", Output: "SrcSubnet", Parameters: "/129"},
+		{Type: "unknown", Input: "SrcAddr", Output: "SrcAddr"},
+	} {
+		nt := Network{TransformNetwork: api.TransformNetwork{Rules: api.NetworkTransformRules{rule}}}
+		require.Error(t, nt.compileRules(opMetrics, ""), "rule %+v", rule)
+	}
+}
+
+func BenchmarkTransformNetwork(b *testing.B) {
+	rules := []api.NetworkTransformRule{
+		{Type: api.OpAddSubnet, Input: "SrcAddr", Output: "SrcSubnet", Parameters: "/24,/64"},
+		{Type: api.OpAddIf, Input: "Bytes", Output: "Big", Parameters: ">1000"},
+		{Type: api.OpAddRegexIf, Input: "SrcAddr", Output: "Internal", Parameters: "^10\\."},
+		{Type: api.OpAddService, Input: "DstPort", Output: "Service", Parameters: "Proto"},
+		{Type: api.OpDecodeProtocol, Input: "Proto", Output: "ProtoName"},
+		{Type: api.OpDecodeTCPFlags, Input: "Flags", Output: "TCPFlags"},
+		{Type: api.OpAddIPCategory, Input: "DstAddr", Output: "DstCategory"},
+		{Type: api.OpAddCommunityID, Output: "CommunityID"},
+		{Type: api.OpAddServerPort, Output: "flow"},
+		{Type: api.OpAddSubnet, Input: "DstAddr", Output: "DstSubnet", Parameters: "/16", If: "inCIDR(DstAddr, '10.0.0.0/8')"},
+	}
+	newTransform := func(b *testing.B, rules []api.NetworkTransformRule) Transformer {
+		tr, err := NewTransformNetwork(operational.NewMetrics(&config.MetricsSettings{NoPanic: true}), config.StageParam{
+			Name: "benchmark_network",
+			Transform: &config.Transform{
+				Network: &api.TransformNetwork{
+					Rules:         rules,
+					ServicesFile:  path.Join("netdb", "testdata", "etcServices.txt"),
+					ProtocolsFile: path.Join("netdb", "testdata", "etcProtocols.txt"),
+					IPCategories: []api.NetworkTransformIPCategory{
+						{Name: "cluster", CIDRs: []string{"10.0.0.0/8"}},
+					},
+				},
+			},
+		})
+		require.NoError(b, err)
+		return tr
+	}
+	entry := config.GenericMap{
+		"SrcAddr":   "10.0.1.2",
+		"DstAddr":   "10.0.3.4",
+		"SrcPort":   45678,
+		"DstPort":   443,
+		"Proto":     6,
+		"Flags":     18,
+		"Bytes":     1500,
+		"Packets":   3,
+		"Interface": "eth0",
+	}
+	run := func(b *testing.B, tr Transformer) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			tr.Transform(entry)
+		}
+	}
+
+	// transforms are created once, since the sub-benchmarks run several times
+	noRules := newTransform(b, nil)
+	b.Run("no rules", func(b *testing.B) {
+		// the copy of the entry
+		run(b, noRules)
+	})
+	allRules := newTransform(b, rules)
+	b.Run("all rules", func(b *testing.B) {
+		run(b, allRules)
+	})
+	for _, rule := range rules {
+		name := rule.Type
+		if rule.If != "" {
+			name += " if"
+		}
+		tr := newTransform(b, []api.NetworkTransformRule{rule})
+		b.Run(name, func(b *testing.B) {
+			run(b, tr)
+		})
+	}
+}
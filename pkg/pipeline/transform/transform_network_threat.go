@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package transform
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	"github.com/netobserv/flowlogs-pipeline/pkg/operational"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/transform/threat"
+	"github.com/netobserv/flowlogs-pipeline/pkg/pipeline/utils"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// threatList is a blocklist, along with the counter of the flows matching it
+type threatList struct {
+	*threat.List
+	matches prometheus.Counter
+}
+
+func validateThreatIntelConfig(info *api.NetworkTransformThreatIntel) error {
+	if info == nil || len(info.Lists) == 0 {
+		return fmt.Errorf("a rule '%s' was found, but there are no threat lists configured", api.OpAddThreatIntel)
+	}
+	names := map[string]struct{}{}
+	for i := range info.Lists {
+		list := &info.Lists[i]
+		if list.Name == "" || list.File == "" {
+			return fmt.Errorf("invalid threat list #%d: name and file are required", i)
+		}
+		if _, ok := names[list.Name]; ok {
+			return fmt.Errorf("invalid threat list %q: duplicate name", list.Name)
+		}
+		names[list.Name] = struct{}{}
+		switch list.GetFormat() {
+		case api.ThreatListFormatList, api.ThreatListFormatCSV, api.ThreatListFormatSTIX:
+		default:
+			return fmt.Errorf("invalid threat list %q: unknown format %q, expected '%s', '%s' or '%s'",
+				list.Name, list.Format, api.ThreatListFormatList, api.ThreatListFormatCSV, api.ThreatListFormatSTIX)
+		}
+	}
+	return nil
+}
+
+// openThreatLists loads the lists and watches their files for changes
+func openThreatLists(opMetrics *operational.Metrics, stage string, tn *api.TransformNetwork) ([]threatList, error) {
+	var lists []threatList
+	for i := range tn.ThreatIntel.Lists {
+		list, err := threat.OpenList(&tn.ThreatIntel.Lists[i])
+		if err != nil {
+			return nil, err
+		}
+		file := tn.ThreatIntel.Lists[i].File
+		utils.WatchFiles([]string{file}, tn.GetReloadPeriod(), func() {
+			if err := list.Reload(); err != nil {
+				log.WithError(err).WithField("list", list.Name()).Error("can't reload threat list. Keeping previous one")
+				return
+			}
+			log.WithField("list", list.Name()).WithField("indicators", list.Size()).Info("threat list reloaded")
+		})
+		lists = append(lists, threatList{
+			List:    list,
+			matches: opMetrics.NewCounter(&threatMatchesCounter, stage, list.Name()),
+		})
+	}
+	return lists, nil
+}
+
+// threatHit is an indicator of the list #list matching the field #field
+type threatHit struct {
+	list     int
+	field    int
+	category string
+}
+
+// compileAddThreatIntel sets the fields matching the threat lists in ThreatMatch, the names of the matched lists in
+// ThreatSource, and the categories of the matched indicators in ThreatCategory
+func (n *Network) compileAddThreatIntel(rule *api.NetworkTransformRule) func(config.GenericMap) {
+	info := n.ThreatIntel
+	fields := append([]string{info.GetSrcIPField(), info.GetDstIPField()}, info.DomainFields...)
+	prefix := outputPrefix(rule)
+	matchField, sourceField, categoryField := prefix+"ThreatMatch", prefix+"ThreatSource", prefix+"ThreatCategory"
+	return func(output config.GenericMap) {
+		// each value is parsed once and looked up in all the lists. Hits are only allocated on matches.
+		var hits []threatHit
+		for f, field := range fields {
+			value, ok := output[field].(string)
+			if !ok || value == "" {
+				continue
+			}
+			ip := net.ParseIP(value)
+			for l := range n.threatLists {
+				var category string
+				if ip != nil {
+					category, ok = n.threatLists[l].LookupIP(ip)
+				} else {
+					category, ok = n.threatLists[l].LookupDomain(value)
+				}
+				if ok {
+					hits = append(hits, threatHit{list: l, field: f, category: category})
+				}
+			}
+		}
+		if len(hits) == 0 {
+			return
+		}
+		// sources and categories are listed in the lists order, and matched fields in the configuration order
+		sort.SliceStable(hits, func(i, j int) bool { return hits[i].list < hits[j].list })
+		var sources, categories, matches []string
+		for i := range hits {
+			if i == 0 || hits[i].list != hits[i-1].list {
+				list := &n.threatLists[hits[i].list]
+				sources = append(sources, list.Name())
+				list.matches.Inc()
+			}
+			if hits[i].category != "" && !containsString(categories, hits[i].category) {
+				categories = append(categories, hits[i].category)
+			}
+		}
+		for f, field := range fields {
+			for i := range hits {
+				if hits[i].field == f {
+					matches = append(matches, field)
+					break
+				}
+			}
+		}
+		output[matchField] = strings.Join(matches, ",")
+		output[sourceField] = strings.Join(sources, ",")
+		if len(categories) > 0 {
+			output[categoryField] = strings.Join(categories, ",")
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package utils
+
+import (
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var fwlog = logrus.WithField("component", "utils.FileWatcher")
+
+// fileWatcher periodically checks a set of files and invokes a callback whenever any of them
+// is modified, created or removed. Polling is used instead of filesystem notifications because
+// files mounted from ConfigMaps or Secrets are replaced through symlink swaps, which are not
+// reliably reported by inotify.
+type fileWatcher struct {
+	paths    []string
+	onChange func()
+	states   map[string]fileState
+}
+
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// WatchFiles starts watching the provided paths every period, until the pipeline exits, as the stages are never
+// stopped before. The current state of the files is taken as the reference, so onChange is only invoked for
+// subsequent modifications.
+func WatchFiles(paths []string, period time.Duration, onChange func()) {
+	w := newFileWatcher(paths, onChange)
+	ticker := time.NewTicker(period)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ExitChannel():
+				return
+			case <-ticker.C:
+				if w.changed() {
+					fwlog.WithField("paths", paths).Debug("change detected")
+					w.onChange()
+				}
+			}
+		}
+	}()
+}
+
+func newFileWatcher(paths []string, onChange func()) *fileWatcher {
+	w := &fileWatcher{
+		paths:    paths,
+		onChange: onChange,
+		states:   map[string]fileState{},
+	}
+	for _, p := range paths {
+		w.states[p] = statFile(p)
+	}
+	return w
+}
+
+// changed returns whether any of the files changed since the last check
+func (w *fileWatcher) changed() bool {
+	changed := false
+	for _, p := range w.paths {
+		st := statFile(p)
+		if st != w.states[p] {
+			w.states[p] = st
+			changed = true
+		}
+	}
+	return changed
+}
+
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, modTime: info.ModTime(), size: info.Size()}
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileWatcher_Changed(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing")
+	missing := filepath.Join(dir, "missing")
+	require.NoError(t, os.WriteFile(existing, []byte("hello"), 0o644))
+
+	w := newFileWatcher([]string{existing, missing}, func() {})
+	require.False(t, w.changed())
+
+	// content modification
+	require.NoError(t, os.WriteFile(existing, []byte("hello world"), 0o644))
+	require.True(t, w.changed())
+	require.False(t, w.changed())
+
+	// file creation
+	require.NoError(t, os.WriteFile(missing, []byte("hi"), 0o644))
+	require.True(t, w.changed())
+	require.False(t, w.changed())
+
+	// file removal
+	require.NoError(t, os.Remove(existing))
+	require.True(t, w.changed())
+	require.False(t, w.changed())
+}
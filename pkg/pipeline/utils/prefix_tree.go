@@ -0,0 +1,134 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package utils
+
+import (
+	"net"
+)
+
+// PrefixTree is a binary trie providing longest-prefix matching over bit strings.
+// It is mostly used to match IP addresses against a set of CIDRs: IPv4 and IPv6 networks are
+// stored under separate roots, so that IPv6 prefixes covering the IPv4-mapped range (such as ::/0)
+// never match IPv4 addresses.
+// A PrefixTree is not safe for concurrent modification; readers may share it once built.
+type PrefixTree struct {
+	root  prefixNode
+	root4 prefixNode
+	size  int
+}
+
+type prefixNode struct {
+	children [2]*prefixNode
+	value    interface{}
+	isSet    bool
+}
+
+func NewPrefixTree() *PrefixTree {
+	return &PrefixTree{}
+}
+
+// Len returns the number of prefixes stored in the tree
+func (t *PrefixTree) Len() int {
+	return t.size
+}
+
+// Insert stores the value for the prefix made of the first `bits` bits of key.
+// Inserting an already existing prefix overrides its value.
+func (t *PrefixTree) Insert(key []byte, bits int, value interface{}) {
+	t.insert(&t.root, key, bits, value)
+}
+
+func (t *PrefixTree) insert(n *prefixNode, key []byte, bits int, value interface{}) {
+	if bits > len(key)*8 {
+		bits = len(key) * 8
+	}
+	for i := 0; i < bits; i++ {
+		b := bitAt(key, i)
+		if n.children[b] == nil {
+			n.children[b] = &prefixNode{}
+		}
+		n = n.children[b]
+	}
+	if !n.isSet {
+		t.size++
+	}
+	n.isSet = true
+	n.value = value
+}
+
+// LongestMatch returns the value of the longest stored prefix matching key, along with
+// the length of that prefix.
+func (t *PrefixTree) LongestMatch(key []byte) (interface{}, int, bool) {
+	return longestMatch(&t.root, key)
+}
+
+func longestMatch(n *prefixNode, key []byte) (interface{}, int, bool) {
+	var value interface{}
+	found := false
+	matchLen := 0
+	for i := 0; ; i++ {
+		if n.isSet {
+			value, matchLen, found = n.value, i, true
+		}
+		if i >= len(key)*8 {
+			break
+		}
+		n = n.children[bitAt(key, i)]
+		if n == nil {
+			break
+		}
+	}
+	return value, matchLen, found
+}
+
+// InsertCIDR stores the value for the given network. IPv4-mapped IPv6 networks of at least 96 bits
+// are stored as IPv4 networks.
+func (t *PrefixTree) InsertCIDR(cidr *net.IPNet, value interface{}) {
+	ones, bits := cidr.Mask.Size()
+	if ip4 := cidr.IP.To4(); ip4 != nil {
+		ones4 := ones
+		if bits == 8*net.IPv6len {
+			ones4 -= 8 * (net.IPv6len - net.IPv4len)
+		}
+		if ones4 >= 0 {
+			t.insert(&t.root4, ip4, ones4, value)
+			return
+		}
+	}
+	if ip := cidr.IP.To16(); ip != nil {
+		t.insert(&t.root, ip, ones, value)
+	}
+}
+
+// LookupIP returns the value of the most specific network containing ip
+func (t *PrefixTree) LookupIP(ip net.IP) (interface{}, bool) {
+	if ip4 := ip.To4(); ip4 != nil {
+		value, _, ok := longestMatch(&t.root4, ip4)
+		return value, ok
+	}
+	ip = ip.To16()
+	if ip == nil {
+		return nil, false
+	}
+	value, _, ok := longestMatch(&t.root, ip)
+	return value, ok
+}
+
+func bitAt(key []byte, i int) byte {
+	return (key[i/8] >> (7 - uint(i%8))) & 1
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright (C) 2026 IBM, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package utils
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustInsertCIDR(t *testing.T, tree *PrefixTree, cidr string, value interface{}) {
+	_, parsed, err := net.ParseCIDR(cidr)
+	require.NoError(t, err)
+	tree.InsertCIDR(parsed, value)
+}
+
+func TestPrefixTree_LookupIP(t *testing.T) {
+	tree := NewPrefixTree()
+	mustInsertCIDR(t, tree, "10.0.0.0/8", "a")
+	mustInsertCIDR(t, tree, "10.1.0.0/16", "b")
+	mustInsertCIDR(t, tree, "10.1.2.3/32", "c")
+	mustInsertCIDR(t, tree, "fd00::/8", "d")
+	mustInsertCIDR(t, tree, "fd00:1::/32", "e")
+	require.Equal(t, 5, tree.Len())
+
+	for ip, expected := range map[string]interface{}{
+		"10.2.0.1":    "a",
+		"10.1.0.1":    "b",
+		"10.1.2.3":    "c",
+		"10.1.2.4":    "b",
+		"fd01::1":     "d",
+		"fd00:1::1":   "e",
+		"11.0.0.1":    nil,
+		"2001:db8::1": nil,
+	} {
+		value, ok := tree.LookupIP(net.ParseIP(ip))
+		require.Equal(t, expected != nil, ok, ip)
+		require.Equal(t, expected, value, ip)
+	}
+
+	_, ok := tree.LookupIP(nil)
+	require.False(t, ok)
+}
+
+func TestPrefixTree_IPv4DoesNotMatchIPv6(t *testing.T) {
+	tree := NewPrefixTree()
+	mustInsertCIDR(t, tree, "0.0.0.0/0", "any4")
+
+	value, ok := tree.LookupIP(net.ParseIP("1.2.3.4"))
+	require.True(t, ok)
+	require.Equal(t, "any4", value)
+
+	_, ok = tree.LookupIP(net.ParseIP("2001:db8::1"))
+	require.False(t, ok)
+
+	mustInsertCIDR(t, tree, "::/0", "any6")
+	value, ok = tree.LookupIP(net.ParseIP("2001:db8::1"))
+	require.True(t, ok)
+	require.Equal(t, "any6", value)
+}
+
+func TestPrefixTree_IPv6DefaultDoesNotMatchIPv4(t *testing.T) {
+	tree := NewPrefixTree()
+	mustInsertCIDR(t, tree, "::/0", "any6")
+	mustInsertCIDR(t, tree, "::/64", "zero6")
+
+	_, ok := tree.LookupIP(net.ParseIP("1.2.3.4"))
+	require.False(t, ok)
+	_, ok = tree.LookupIP(net.ParseIP("::ffff:1.2.3.4"))
+	require.False(t, ok)
+
+	value, ok := tree.LookupIP(net.ParseIP("2001:db8::1"))
+	require.True(t, ok)
+	require.Equal(t, "any6", value)
+
+	// IPv4-mapped networks are IPv4 networks
+	mustInsertCIDR(t, tree, "::ffff:10.0.0.0/104", "mapped")
+	value, ok = tree.LookupIP(net.ParseIP("10.1.2.3"))
+	require.True(t, ok)
+	require.Equal(t, "mapped", value)
+}
+
+func TestPrefixTree_LongestMatch(t *testing.T) {
+	tree := NewPrefixTree()
+	tree.Insert([]byte{0xAB, 0xCD}, 12, "ab-c")
+	tree.Insert([]byte{0xAB}, 8, "ab")
+	// override
+	tree.Insert([]byte{0xAB}, 8, "AB")
+	require.Equal(t, 2, tree.Len())
+
+	value, matched, ok := tree.LongestMatch([]byte{0xAB, 0xCF})
+	require.True(t, ok)
+	require.Equal(t, "ab-c", value)
+	require.Equal(t, 12, matched)
+
+	value, matched, ok = tree.LongestMatch([]byte{0xAB, 0x00})
+	require.True(t, ok)
+	require.Equal(t, "AB", value)
+	require.Equal(t, 8, matched)
+
+	_, _, ok = tree.LongestMatch([]byte{0xAA})
+	require.False(t, ok)
+}
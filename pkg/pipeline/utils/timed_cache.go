@@ -91,6 +91,24 @@ func (tc *TimedCache) GetCacheLen() int {
 	return tc.cacheList.Len()
 }
 
+// RemoveCacheEntry removes an entry from the cache, without invoking any expiry callback
+func (tc *TimedCache) RemoveCacheEntry(key string) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if cEntry, ok := tc.cacheMap[key]; ok {
+		tc.cacheList.Remove(cEntry.e)
+		delete(tc.cacheMap, key)
+	}
+}
+
+// Clear removes all the entries from the cache, without invoking any expiry callback
+func (tc *TimedCache) Clear() {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.cacheList.Init()
+	tc.cacheMap = make(TimedCacheMap)
+}
+
 // We expect that the function calling Iterate might make updates to the entries by calling UpdateCacheEntry()
 // We therefore cannot take the lock at this point since it will conflict with the call in UpdateCacheEntry()
 // TODO: If the callback needs to update the cache, then we need a method to perform it without taking the lock again.